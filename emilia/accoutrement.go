@@ -11,33 +11,41 @@ const (
 	disableOption   = `nil`
 	delimiterOption = ':'
 
-	optionDraft           = `draft`
-	optionTomb            = `tomb`
-	optionAuthorImage     = `author-image`
-	optionMath            = `math`
-	optionExcludeHtmlHead = `exclude-html-head`
-	optionPreview         = `preview`
-	optionPreviewWidth    = `preview-width`
-	optionPreviewHeigh    = `preview-height`
-	optionPreviewGenerate = `preview-generate`
-	optionToc             = `toc`
-	optionRssPrefix       = `rss-prefix`
-	optionRssTitle        = `rss-title`
+	optionDraft             = `draft`
+	optionTomb              = `tomb`
+	optionAuthorImage       = `author-image`
+	optionMath              = `math`
+	optionExcludeHtmlHead   = `exclude-html-head`
+	optionPreview           = `preview`
+	optionPreviewWidth      = `preview-width`
+	optionPreviewHeigh      = `preview-height`
+	optionPreviewGenerate   = `preview-generate`
+	optionToc               = `toc`
+	optionRssPrefix         = `rss-prefix`
+	optionRssTitle          = `rss-title`
+	optionEnclosure         = `enclosure`
+	optionEnclosureDuration = `enclosure-duration`
+	optionEpisode           = `episode`
+	optionExplicit          = `explicit`
 )
 
 var accoutrementActions = map[string]func(string, *yunyun.Accoutrement){
-	optionDraft:           accoutrementDraft,
-	optionTomb:            accoutrementTomb,
-	optionAuthorImage:     accoutrementAuthorImage,
-	optionMath:            accoutrementMath,
-	optionExcludeHtmlHead: accoutrementExcludeHtmlScript,
-	optionPreview:         accoutrementPreview,
-	optionPreviewWidth:    accoutrementPreviewWidth,
-	optionPreviewHeigh:    accoutrementPreviewHeight,
-	optionPreviewGenerate: accoutrementPreviewGenerate,
-	optionToc:             accoutrementToc,
-	optionRssPrefix:       accoutrementRssPrefix,
-	optionRssTitle:        accoutrementRssTitle,
+	optionDraft:             accoutrementDraft,
+	optionTomb:              accoutrementTomb,
+	optionAuthorImage:       accoutrementAuthorImage,
+	optionMath:              accoutrementMath,
+	optionExcludeHtmlHead:   accoutrementExcludeHtmlScript,
+	optionPreview:           accoutrementPreview,
+	optionPreviewWidth:      accoutrementPreviewWidth,
+	optionPreviewHeigh:      accoutrementPreviewHeight,
+	optionPreviewGenerate:   accoutrementPreviewGenerate,
+	optionToc:               accoutrementToc,
+	optionRssPrefix:         accoutrementRssPrefix,
+	optionRssTitle:          accoutrementRssTitle,
+	optionEnclosure:         accoutrementEnclosure,
+	optionEnclosureDuration: accoutrementEnclosureDuration,
+	optionEpisode:           accoutrementEpisode,
+	optionExplicit:          accoutrementExplicit,
 }
 
 // InitializeAccoutrement fills accoutrement according to the config
@@ -53,28 +61,38 @@ func InitializeAccoutrement(tombs []yunyun.RelativePathDir, page *yunyun.Page) {
 	}
 }
 
-// FillAccoutrement parses `options` and fills the `target`.
-func FillAccoutrement(tombs []yunyun.RelativePathDir, options *string, page *yunyun.Page) {
+// FillAccoutrement parses `options` and fills the `target`, returns
+// the keys of the options that are not known.
+func FillAccoutrement(tombs []yunyun.RelativePathDir, options *string, page *yunyun.Page) []string {
 	// Let's first initialize it before filling.
 	InitializeAccoutrement(tombs, page)
-	// Exit immediately if it's an empty string.
-	if len(*options) < 1 {
-		return
-	}
-	for _, option := range strings.Split(*options, " ") {
+	unknown := make([]string, 0)
+	for _, option := range strings.Fields(*options) {
 		key, value := breakOption(option)
-		// If action is found, then execute it.
-		if action, ok := accoutrementActions[key]; ok {
-			action(value, page.Accoutrement)
+		if !SetAccoutrementOption(key, value, page.Accoutrement) {
+			unknown = append(unknown, key)
 		}
 	}
+	return unknown
+}
+
+// SetAccoutrementOption sets a single option by its key on the `target`,
+// returns false if the key is not a known option.
+func SetAccoutrementOption(key, value string, target *yunyun.Accoutrement) bool {
+	action, ok := accoutrementActions[key]
+	// If action is found, then execute it.
+	if ok {
+		action(value, target)
+	}
+	return ok
 }
 
 // breakOption breaks the option into two parts, the first part is the
-// key, and the second part is the value. If the option doesn't have
-// a value, then the second part is `enableOption` by default.
+// key, and the second part is the value, which may have delimiters of
+// its own, like urls. If the option doesn't have a value, then the
+// second part is `enableOption` by default.
 func breakOption(what string) (string, string) {
-	for i := len(what) - 1; i >= 0; i-- {
+	for i := 0; i < len(what); i++ {
 		if what[i] == delimiterOption {
 			return what[:i], what[i+1:]
 		}
@@ -144,6 +162,26 @@ func accoutrementRssTitle(what string, target *yunyun.Accoutrement) {
 	target.RssTitle = what
 }
 
+// accoutrementEnclosure sets the enclosure option of the accoutrement.
+func accoutrementEnclosure(what string, target *yunyun.Accoutrement) {
+	target.Enclosure = what
+}
+
+// accoutrementEnclosureDuration sets the enclosure duration option of the accoutrement.
+func accoutrementEnclosureDuration(what string, target *yunyun.Accoutrement) {
+	target.EnclosureDuration = what
+}
+
+// accoutrementEpisode sets the episode option of the accoutrement.
+func accoutrementEpisode(what string, target *yunyun.Accoutrement) {
+	target.Episode = what
+}
+
+// accoutrementExplicit sets the explicit option of the accoutrement.
+func accoutrementExplicit(what string, target *yunyun.Accoutrement) {
+	accoutrementBool(what, &target.Explicit)
+}
+
 // accoutrementBool sets the bool value of the target according to the what.
 func accoutrementBool(what string, target *yunyun.AccoutrementFlip) {
 	switch strings.TrimSpace(what) {
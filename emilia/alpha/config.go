@@ -6,10 +6,12 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/thecsw/darkness/emilia/alpha/roxy"
+	"github.com/thecsw/darkness/emilia/kaori"
 	"github.com/thecsw/darkness/emilia/puck"
 	"github.com/thecsw/darkness/yunyun"
 	"github.com/thecsw/gana"
@@ -20,6 +22,7 @@ func BuildConfig(options Options) *DarknessConfig {
 	conf := &DarknessConfig{}
 	conf.Runtime.Logger = puck.NewLogger("Alpha ☕")
 	conf.Runtime.WorkDir = WorkingDirectory(options.WorkDir)
+	conf.Runtime.ConfigPath = options.DarknessConfig
 
 	// Record the time it takes to initialize the options.
 	defer puck.Stopwatch("Initialized options").Record(conf.Runtime.Logger)
@@ -43,6 +46,9 @@ func BuildConfig(options Options) *DarknessConfig {
 		conf.Runtime.Logger.Fatal("Decoding config", "path", options.DarknessConfig, "err", err)
 	}
 
+	// Set up where the site is going to be built.
+	conf.setupOutputDirectory(options)
+
 	// Define the preview filename.
 	if isUnset(conf.Website.Preview) {
 		conf.Website.Preview = puck.DefaultPreviewFile
@@ -58,6 +64,14 @@ func BuildConfig(options Options) *DarknessConfig {
 			conf.Runtime.Logger.Error("Getting working directory, no config url found", "err", err)
 			os.Exit(1)
 		}
+		// Local links have to point into the built site.
+		if conf.HasOutputDirectory() {
+			conf.Url, err = filepath.Abs(string(conf.Runtime.OutputDir))
+			if err != nil {
+				conf.Runtime.Logger.Error("Getting output directory, no config url found", "err", err)
+				os.Exit(1)
+			}
+		}
 	}
 
 	// Check if custom Url has been passed
@@ -96,6 +110,20 @@ func BuildConfig(options Options) *DarknessConfig {
 		conf.Runtime.PluginConfigs[provider] = prv
 	}
 
+	// Make sure all the feeds can be built.
+	for i := range conf.Feeds {
+		feed := &conf.Feeds[i]
+		if isUnset(feed.Output) {
+			conf.Runtime.Logger.Fatal("Feed has no output", "feed", i+1)
+		}
+		if isUnset(feed.Format) {
+			feed.Format = FeedFormatRss
+		}
+		if !slices.Contains([]string{FeedFormatRss, FeedFormatAtom, FeedFormatJson}, feed.Format) {
+			conf.Runtime.Logger.Fatal("Unknown feed format", "output", feed.Output, "format", feed.Format)
+		}
+	}
+
 	// Set up the custom highlight languages if they exist.
 	conf.setupHighlightJsLanguages()
 
@@ -104,6 +132,14 @@ func BuildConfig(options Options) *DarknessConfig {
 		conf.Website.SyntaxHighlightingTheme = highlightJsThemeDefaultPath
 	}
 
+	// Set up the static highlight theme and make sure we have it.
+	if isUnset(conf.Website.SyntaxHighlightingStaticTheme) {
+		conf.Website.SyntaxHighlightingStaticTheme = kaori.DefaultTheme
+	}
+	if _, ok := kaori.Theme(conf.Website.SyntaxHighlightingStaticTheme); !ok {
+		conf.Runtime.Logger.Fatal("Unknown syntax highlighting theme", "theme", conf.Website.SyntaxHighlightingStaticTheme)
+	}
+
 	// Set the default vendor directory if it's not set.
 	if isUnset(conf.Project.DarknessVendorDirectory) {
 		conf.Project.DarknessVendorDirectory = puck.DefaultVendorDirectory
@@ -114,6 +150,9 @@ func BuildConfig(options Options) *DarknessConfig {
 		conf.Project.DarknessPreviewDirectory = puck.DefaultPreviewDirectory
 	}
 
+	// Make sure we can tell which assets to publish.
+	conf.setupPublish()
+
 	// Build the regex that will be used to exclude files that
 	// have been denoted in emilia darkness config.
 	if len(conf.Project.Exclude) > 0 {
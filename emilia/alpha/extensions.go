@@ -1,15 +1,54 @@
 package alpha
 
 import (
+	"fmt"
+	"path/filepath"
+
 	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/gana"
 )
 
+// InputExtensions is the list of input formats, which can be given
+// in the config as either a single string or a list of strings.
+type InputExtensions []string
+
+// UnmarshalTOML decodes both `input = ".org"` and `input = [".org", ".md"]`.
+func (i *InputExtensions) UnmarshalTOML(data any) error {
+	switch value := data.(type) {
+	case string:
+		*i = InputExtensions{value}
+	case []any:
+		extensions := make(InputExtensions, 0, len(value))
+		for _, v := range value {
+			ext, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("input extension must be a string, got %T", v)
+			}
+			extensions = append(extensions, ext)
+		}
+		*i = extensions
+	default:
+		return fmt.Errorf("input must be a string or a list of strings, got %T", data)
+	}
+	return nil
+}
+
+// Has returns true if the extension is one of the inputs.
+func (i InputExtensions) Has(ext string) bool {
+	return gana.Anyf(func(v string) bool { return v == ext }, i)
+}
+
+// HasFile returns true if the file has one of the input extensions.
+func (i InputExtensions) HasFile(filename string) bool {
+	return i.Has(filepath.Ext(filename))
+}
+
 // setupProjectExtensions sets up the input/output extensions for the project.
 func (conf *DarknessConfig) setupProjectExtensions(options Options) {
 	// If input/output formats are empty, default to .org/.html respectively.
-	if isUnset(conf.Project.Input) {
+	if len(conf.Project.Input) < 1 {
 		conf.Runtime.Logger.Warn("Input format not found, using a default", "ext", puck.ExtensionOrgmode)
-		conf.Project.Input = puck.ExtensionOrgmode
+		conf.Project.Input = InputExtensions{puck.ExtensionOrgmode}
 	}
 
 	// Output section.
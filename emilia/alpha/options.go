@@ -22,4 +22,7 @@ type Options struct {
 
 	// VendorGalleries dictates whether we should stub in local gallery images.
 	VendorGalleries bool
+
+	// OutputDirectory overrides the output directory, can be anywhere.
+	OutputDirectory string
 }
@@ -0,0 +1,47 @@
+package alpha
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/puck"
+)
+
+// setupOutputDirectory sets up the directory where the site gets built.
+func (conf *DarknessConfig) setupOutputDirectory(options Options) {
+	// Overrides are not cleaned up by darkness, so they can be anywhere.
+	if !isUnset(options.OutputDirectory) {
+		conf.Runtime.OutputDir = WorkingDirectory(filepath.Clean(options.OutputDirectory))
+		return
+	}
+
+	// By default, outputs are written right next to their sources.
+	conf.Runtime.OutputDir = conf.Runtime.WorkDir
+	if isUnset(conf.Project.OutputDirectory) {
+		return
+	}
+
+	// The output directory gets removed with everything inside of it
+	// when cleaning, so it must be a child of the working directory.
+	dir := filepath.Clean(string(conf.Project.OutputDirectory))
+	if filepath.IsAbs(dir) || dir == "." || dir == ".." || strings.HasPrefix(dir, "../") {
+		conf.Runtime.Logger.Fatal("Output directory must be inside of the working directory",
+			"dir", conf.Project.OutputDirectory)
+	}
+	conf.Runtime.OutputDir = WorkingDirectory(conf.Runtime.WorkDir.JoinGeneric(dir))
+}
+
+// HasOutputDirectory returns true if the site is built into a separate directory.
+func (conf *DarknessConfig) HasOutputDirectory() bool {
+	return conf.Runtime.OutputDir != conf.Runtime.WorkDir
+}
+
+// IsOutputDirectory returns true if the path is the separate output directory.
+func (conf *DarknessConfig) IsOutputDirectory(path string) bool {
+	return conf.HasOutputDirectory() && filepath.Clean(path) == filepath.Clean(string(conf.Runtime.OutputDir))
+}
+
+// IsThemeDirectory returns true if the path is the website's theme directory.
+func (conf *DarknessConfig) IsThemeDirectory(path string) bool {
+	return filepath.Clean(path) == filepath.Clean(conf.Runtime.WorkDir.JoinGeneric(string(puck.ThemeDirectory)))
+}
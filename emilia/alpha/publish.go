@@ -0,0 +1,53 @@
+package alpha
+
+import (
+	"path"
+	"path/filepath"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// setupPublish makes sure that the publish patterns are valid.
+func (conf *DarknessConfig) setupPublish() {
+	for _, pattern := range append(conf.Project.Publish, conf.Project.PublishExclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			conf.Runtime.Logger.Fatal("Bad publish pattern", "pattern", pattern, "err", err)
+		}
+	}
+}
+
+// ShouldPublish returns true if the asset, relative to the working
+// directory, should be published into the output directory.
+func (conf *DarknessConfig) ShouldPublish(asset yunyun.RelativePathFile) bool {
+	relative := filepath.ToSlash(string(asset))
+	// The pages always need what akane has vendored or generated.
+	if matchesPath(path.Clean(filepath.ToSlash(string(conf.Project.DarknessVendorDirectory))), relative) ||
+		matchesPath(path.Clean(filepath.ToSlash(string(conf.Project.DarknessPreviewDirectory))), relative) {
+		return true
+	}
+	for _, pattern := range conf.Project.PublishExclude {
+		if matchesPath(pattern, relative) {
+			return false
+		}
+	}
+	if len(conf.Project.Publish) < 1 {
+		return true
+	}
+	for _, pattern := range conf.Project.Publish {
+		if matchesPath(pattern, relative) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPath returns true if the pattern matches the path or any of
+// its parent directories, so that `assets` takes everything inside it.
+func matchesPath(pattern, relative string) bool {
+	for current := relative; current != "." && current != "/"; current = path.Dir(current) {
+		if matched, _ := path.Match(pattern, current); matched {
+			return true
+		}
+	}
+	return false
+}
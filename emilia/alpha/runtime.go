@@ -16,6 +16,13 @@ type RuntimeConfig struct {
 	// WorkDir is the directory of where darkness project lives.
 	WorkDir WorkingDirectory
 
+	// OutputDir is the directory where the site gets built, which
+	// is the same as `WorkDir` unless the output directory is set.
+	OutputDir WorkingDirectory
+
+	// ConfigPath is the path of the darkness config file.
+	ConfigPath string
+
 	// Slice with just `Url` in it.
 	urlSlice []string
 
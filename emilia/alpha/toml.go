@@ -30,6 +30,9 @@ type DarknessConfig struct {
 	// Website is the website section of the config
 	Website WebsiteConfig `toml:"website"`
 
+	// Feeds are the feeds built together with `misa -feeds`.
+	Feeds []FeedConfig `toml:"feeds"`
+
 	// Providers is the directories of provider libraries
 	Providers map[string]yunyun.RelativePathFile `toml:"providers"`
 
@@ -43,12 +46,16 @@ type DarknessConfig struct {
 // ProjectConfig is the project section of the config
 type ProjectConfig struct {
 	ExcludeRegex *regexp.Regexp `toml:"-"`
-	// Input is the input format (default ".org")
-	Input string `toml:"input"`
+	// Input is the list of input formats (default ".org")
+	Input InputExtensions `toml:"input"`
 
 	// Output is the output format (defaulte ".html")
 	Output string `toml:"output"`
 
+	// OutputDirectory is where to build the site, defaults to building
+	// the outputs right next to their sources.
+	OutputDirectory yunyun.RelativePathDir `toml:"output_directory"`
+
 	// DarknessVendorDirectory where to vendor, default to `darkness_vendor`.
 	DarknessVendorDirectory yunyun.RelativePathDir `toml:"vendor_directory"`
 
@@ -58,6 +65,19 @@ type ProjectConfig struct {
 	// Excludes is the list of relative paths to exclude from the project
 	Exclude []yunyun.RelativePathDir `toml:"exclude"`
 
+	// Publish is the list of glob patterns of the assets to copy into
+	// the output directory, where a matched directory takes everything
+	// inside of it. Everything that is not an input is copied if empty.
+	//
+	// Example: ["css", "scripts", "assets/*.png"]
+	Publish []string `toml:"publish"`
+
+	// PublishExclude is the list of glob patterns of the assets that
+	// are never copied into the output directory.
+	//
+	// Example: ["drafts", "*.psd"]
+	PublishExclude []string `toml:"publish_exclude"`
+
 	ExcludeEnabled bool `toml:"-"`
 }
 
@@ -99,6 +119,15 @@ type WebsiteConfig struct {
 	// syntax highlighting with highlight.js
 	SyntaxHighlighting bool `toml:"syntax_highlighting"`
 
+	// SyntaxHighlightingStatic colors the code blocks when building,
+	// so that the pages don't need highlight.js, which works on its own
+	// without `syntax_highlighting`
+	SyntaxHighlightingStatic bool `toml:"syntax_highlighting_static"`
+
+	// SyntaxHighlightingStaticTheme is the built-in theme of the static
+	// highlighting, like "agate" (default), "default", or "github"
+	SyntaxHighlightingStaticTheme string `toml:"syntax_highlighting_static_theme"`
+
 	// ClickableImages marks whether the images should href to the img link.
 	ClickableImages bool `toml:"clickable_images"`
 
@@ -107,6 +136,46 @@ type WebsiteConfig struct {
 
 	// RomanFootnotes tells if we have to use roman numerals for footnotes
 	RomanFootnotes bool `toml:"roman_footnotes"`
+
+	// Sitemap is the sitemap file to generate after every build,
+	// no sitemap is generated if empty
+	Sitemap yunyun.RelativePathFile `toml:"sitemap"`
+
+	// Tags is the directory to generate the tag pages in after every
+	// build, like "tags", no tag pages are generated if empty
+	Tags yunyun.RelativePathDir `toml:"tags"`
+
+	// DirectoryIndexes generates a listing page after every build for
+	// the directories that have pages under them, but no page of their own
+	DirectoryIndexes bool `toml:"directory_indexes"`
+
+	// Archive is the directory to generate the archive page in after
+	// every build, like "archive", no archive is generated if empty
+	Archive yunyun.RelativePathDir `toml:"archive"`
+
+	// Backlinks adds the "Linked from" section to the pages, which
+	// lists the pages that link to them
+	Backlinks bool `toml:"backlinks"`
+
+	// Navigation adds the previous and next links to the pages, which
+	// follow the page's series or the dated pages of its directory
+	Navigation bool `toml:"navigation"`
+
+	// LinkGraph is the json file of the links between the pages to
+	// generate after every build, no graph is generated if empty
+	LinkGraph yunyun.RelativePathFile `toml:"link_graph"`
+
+	// Search builds the search index after every build and adds the
+	// search widget to the pages, which works without any services
+	Search bool `toml:"search"`
+
+	// SearchShards splits the search index by the top directories, so
+	// that large websites don't need to be downloaded all at once
+	SearchShards bool `toml:"search_shards"`
+
+	// Offline makes the pages only use local assets, the remote scripts
+	// are vendored and the remote embeds only load when clicked
+	Offline bool `toml:"offline"`
 }
 
 // AuthorConfig is the author section of the config
@@ -200,4 +269,62 @@ type RSSConfig struct {
 	// DefaultHour defines the hour value in RSS timestamp if one
 	// is not provided. Use the 24 hrs.
 	DefaultHour int `toml:"default_hour"`
+
+	// FullContent embeds the whole exported pages in the RSS items,
+	// so that readers don't need to leave their feed readers.
+	FullContent bool `toml:"full_content"`
+
+	// Podcast is for the podcast apps, used when pages have enclosures.
+	Podcast PodcastConfig `toml:"podcast"`
+}
+
+// PodcastConfig fills the iTunes elements of the rss channel.
+type PodcastConfig struct {
+	// Image is the podcast's artwork, relative to the website or a url.
+	Image string `toml:"image"`
+
+	// Category is one of Apple's podcast categories.
+	//
+	// Example: "Technology"
+	Category string `toml:"category"`
+
+	// Explicit tells whether the podcast has explicit content.
+	Explicit bool `toml:"explicit"`
+
+	// OwnerName and OwnerEmail are the podcast owner's contact.
+	OwnerName  string `toml:"owner_name"`
+	OwnerEmail string `toml:"owner_email"`
+
+	// Type is either "episodic" (default) or "serial".
+	Type string `toml:"type"`
+}
+
+// Feed formats that can be given in the feeds section.
+const (
+	FeedFormatRss  = "rss"
+	FeedFormatAtom = "atom"
+	FeedFormatJson = "json"
+)
+
+// FeedConfig is a single feed of the feeds section.
+type FeedConfig struct {
+	// Output is where the feed is written, relative to the output.
+	//
+	// Example: "blog/feed.xml"
+	Output yunyun.RelativePathFile `toml:"output"`
+
+	// Format is one of "rss", "atom", or "json", defaults to "rss".
+	Format string `toml:"format"`
+
+	// Title is the feed's title, defaults to the website's title.
+	Title string `toml:"title"`
+
+	// Directories only includes pages under these directories.
+	Directories []string `toml:"directories"`
+
+	// Tags only includes pages that have any of these tags.
+	Tags []string `toml:"tags"`
+
+	// Limit is the maximum number of entries, no limit if unset.
+	Limit int `toml:"limit"`
 }
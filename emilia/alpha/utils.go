@@ -1,12 +1,16 @@
 package alpha
 
 import (
+	"path/filepath"
 	"strings"
 
 	"github.com/thecsw/darkness/yunyun"
 )
 
-// InputFilenameToOutput converts input filename to the filename to write.
-func (p ProjectConfig) InputFilenameToOutput(file yunyun.FullPathFile) string {
-	return strings.Replace(string(file), p.Input, p.Output, 1)
+// InputFilenameToOutput converts input filename to the filename to write,
+// which is placed under the output directory if there is one.
+func (conf *DarknessConfig) InputFilenameToOutput(file yunyun.FullPathFile) string {
+	relative := string(conf.Runtime.WorkDir.Rel(file))
+	relative = strings.TrimSuffix(relative, filepath.Ext(relative)) + conf.Project.Output
+	return conf.Runtime.OutputDir.JoinGeneric(relative)
 }
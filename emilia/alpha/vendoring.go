@@ -10,8 +10,8 @@ import (
 
 // setupGalleryVendoring sets up the vendoring of galleries.
 func (conf *DarknessConfig) setupGalleryVendoring(options Options) {
-	// Work through the vendored galleries.
-	conf.Runtime.VendorGalleries = options.VendorGalleries
+	// Work through the vendored galleries, which offline websites need.
+	conf.Runtime.VendorGalleries = options.VendorGalleries || conf.Website.Offline
 
 	// If we're not vendoring, then we're done.
 	if !conf.Runtime.VendorGalleries {
@@ -0,0 +1,17 @@
+package kaori
+
+func init() {
+	register("bash", &lexer{
+		lineComments: []string{"#"},
+		quotes:       []string{`"`},
+		rawQuotes:    []string{`'`},
+		keywords: newWords(`if then else elif fi for while until do done case esac in
+			function select return break continue`),
+		builtins: newWords(`alias cd declare echo eval exec exit export kill local printf
+			pwd read readonly set shift source test trap type unset wait`),
+		literals:       newWords(`true false`),
+		titleAfter:     newWords(`function`),
+		metaPrefix:     "#!",
+		variablePrefix: "$",
+	}, "sh", "shell", "zsh")
+}
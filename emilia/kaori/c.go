@@ -0,0 +1,42 @@
+package kaori
+
+// cKeywords are the keywords of c, which c++ has too.
+const cKeywords = `auto break case const continue default do else enum extern for goto
+	if inline register restrict return sizeof static struct switch typedef union volatile while`
+
+// cTypes are the types of c, which c++ has too.
+const cTypes = `bool char double float int long short signed unsigned void size_t ssize_t
+	int8_t int16_t int32_t int64_t uint8_t uint16_t uint32_t uint64_t FILE`
+
+// cBuiltins are the functions of c's standard library.
+const cBuiltins = `printf fprintf sprintf snprintf scanf malloc calloc realloc free memcpy
+	memset memmove strlen strcmp strncmp strcpy strncpy strcat fopen fclose fread fwrite
+	puts getchar putchar exit abort assert`
+
+func init() {
+	register("c", &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        []string{`"`, `'`},
+		keywords:      newWords(cKeywords),
+		types:         newWords(cTypes),
+		builtins:      newWords(cBuiltins),
+		literals:      newWords(`true false NULL`),
+		metaPrefix:    "#",
+	}, "h")
+	register("cpp", &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        []string{`"`, `'`},
+		keywords: newWords(cKeywords + ` alignas alignof catch class concept consteval
+			constexpr constinit const_cast co_await co_return co_yield decltype delete
+			dynamic_cast explicit export friend mutable namespace new noexcept operator
+			override final private protected public reinterpret_cast requires static_assert
+			static_cast template this thread_local throw try typeid typename using virtual`),
+		types:      newWords(cTypes + ` wchar_t char8_t char16_t char32_t string vector map set unordered_map`),
+		builtins:   newWords(cBuiltins + ` std cout cin cerr endl make_shared make_unique move forward`),
+		literals:   newWords(`true false nullptr NULL`),
+		titleAfter: newWords(`class struct namespace`),
+		metaPrefix: "#",
+	}, "c++", "cc", "cxx", "hpp")
+}
@@ -0,0 +1,28 @@
+package kaori
+
+func init() {
+	register("json", &lexer{
+		quotes:    []string{`"`},
+		literals:  newWords(`true false null`),
+		keySuffix: ":",
+	}, "jsonc")
+	register("yaml", &lexer{
+		lineComments:    []string{"#"},
+		quotes:          []string{`"`},
+		rawQuotes:       []string{`'`},
+		literals:        newWords(`true false null yes no on off True False Null Yes No`),
+		identifierRunes: "-.",
+		metaPrefix:      "---",
+		keySuffix:       ":",
+	}, "yml")
+	register("toml", &lexer{
+		lineComments:    []string{"#"},
+		quotes:          []string{`"""`, `"`},
+		rawQuotes:       []string{`'''`, `'`},
+		literals:        newWords(`true false inf nan`),
+		identifierRunes: "-",
+		metaPrefix:      "[",
+		metaClass:       "section",
+		keySuffix:       "=",
+	})
+}
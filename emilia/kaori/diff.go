@@ -0,0 +1,32 @@
+package kaori
+
+import "strings"
+
+func init() {
+	register("diff", tokenizerFunc(tokenizeDiff), "patch")
+}
+
+// tokenizeDiff colors the diff line by line.
+func tokenizeDiff(code string) []token {
+	tokens := make([]token, 0, strings.Count(code, "\n")+1)
+	for _, line := range strings.SplitAfter(code, "\n") {
+		text := strings.TrimSuffix(line, "\n")
+		class := ""
+		switch {
+		case strings.HasPrefix(text, "+++"), strings.HasPrefix(text, "---"),
+			strings.HasPrefix(text, "diff "), strings.HasPrefix(text, "index "),
+			strings.HasPrefix(text, "@@"):
+			class = "meta"
+		case strings.HasPrefix(text, "+"), strings.HasPrefix(text, ">"):
+			class = "addition"
+		case strings.HasPrefix(text, "-"), strings.HasPrefix(text, "<"):
+			class = "deletion"
+		}
+		if len(class) < 1 || len(text) < 1 {
+			tokens = append(tokens, token{text: line})
+			continue
+		}
+		tokens = append(tokens, token{class: class, text: text}, token{text: line[len(text):]})
+	}
+	return tokens
+}
@@ -0,0 +1,19 @@
+package kaori
+
+func init() {
+	register("go", &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        []string{`"`, `'`},
+		rawQuotes:     []string{"`"},
+		keywords: newWords(`break case chan const continue default defer else fallthrough
+			for func go goto if import interface map package range return select struct
+			switch type var`),
+		types: newWords(`any bool byte comparable complex64 complex128 error float32 float64
+			int int8 int16 int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr`),
+		builtins: newWords(`append cap clear close complex copy delete imag len make max min
+			new panic print println real recover`),
+		literals:   newWords(`true false nil iota`),
+		titleAfter: newWords(`func`),
+	}, "golang")
+}
@@ -0,0 +1,17 @@
+package kaori
+
+func init() {
+	register("haskell", &lexer{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"{-", "-}"}},
+		quotes:        []string{`"`},
+		keywords: newWords(`as case class data default deriving do else forall hiding if
+			import in infix infixl infixr instance let module newtype of qualified then
+			type where`),
+		types: newWords(`Bool Char Double Either Float IO Int Integer Maybe Ordering String`),
+		builtins: newWords(`map filter foldl foldr head tail length print putStrLn show read
+			return pure fmap mapM mapM_ sequence zip lines words concat`),
+		literals:        newWords(`True False Nothing Just Left Right LT EQ GT`),
+		identifierRunes: "'",
+	}, "hs")
+}
@@ -0,0 +1,18 @@
+package kaori
+
+func init() {
+	register("java", &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        []string{`"""`, `"`, `'`},
+		keywords: newWords(`abstract assert break case catch class continue default do else
+			enum extends final finally for if implements import instanceof interface native
+			new package permits private protected public record return sealed static super
+			switch synchronized this throw throws transient try var volatile while yield`),
+		types:      newWords(`boolean byte char double float int long short void String Object Integer Long Double`),
+		builtins:   newWords(`System Math List Map Set ArrayList HashMap Arrays Collections Optional`),
+		literals:   newWords(`true false null`),
+		titleAfter: newWords(`class interface enum record`),
+		metaPrefix: "@",
+	})
+}
@@ -0,0 +1,36 @@
+package kaori
+
+// javascriptKeywords are the keywords of javascript, which typescript has too.
+const javascriptKeywords = `async await break case catch class const continue debugger default
+	delete do else export extends finally for from function if import in instanceof let new
+	of return static super switch this throw try typeof var void while with yield`
+
+// javascriptBuiltins are the objects of javascript, which typescript has too.
+const javascriptBuiltins = `Array Boolean Date Error JSON Map Math Number Object Promise Proxy
+	Reflect RegExp Set String Symbol WeakMap WeakSet console document window globalThis
+	module require process`
+
+func init() {
+	register("javascript", &lexer{
+		lineComments:    []string{"//"},
+		blockComments:   [][2]string{{"/*", "*/"}},
+		quotes:          []string{`"`, `'`, "`"},
+		keywords:        newWords(javascriptKeywords),
+		builtins:        newWords(javascriptBuiltins),
+		literals:        newWords(`true false null undefined NaN Infinity`),
+		titleAfter:      newWords(`function class`),
+		identifierRunes: "$",
+	}, "js", "jsx", "mjs", "node")
+	register("typescript", &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        []string{`"`, `'`, "`"},
+		keywords: newWords(javascriptKeywords + ` abstract as declare enum implements
+			interface keyof namespace private protected public readonly type`),
+		types:           newWords(`any bigint boolean never number object string symbol unknown void`),
+		builtins:        newWords(javascriptBuiltins),
+		literals:        newWords(`true false null undefined NaN Infinity`),
+		titleAfter:      newWords(`function class interface`),
+		identifierRunes: "$",
+	}, "ts", "tsx")
+}
@@ -0,0 +1,97 @@
+package kaori
+
+import (
+	"embed"
+	"html"
+	"strings"
+)
+
+const (
+	// DefaultTheme is the theme used by default, same as with highlight.js.
+	DefaultTheme = "agate"
+	// classPrefix is the prefix of highlight.js class names.
+	classPrefix = "hljs-"
+)
+
+var (
+	//go:embed themes/*.css
+	themes embed.FS
+)
+
+// token is a piece of code with the same color.
+type token struct {
+	// class is the highlight.js class of the token, empty if plain.
+	class string
+	// text is the token's code.
+	text string
+}
+
+// tokenizer splits the code into tokens.
+type tokenizer interface {
+	tokenize(code string) []token
+}
+
+// tokenizerFunc is a tokenizer that's just a function.
+type tokenizerFunc func(code string) []token
+
+// tokenize calls the function.
+func (f tokenizerFunc) tokenize(code string) []token { return f(code) }
+
+// languages are the tokenizers by the languages' names.
+var languages = map[string]tokenizer{}
+
+// aliases are the other names of the languages.
+var aliases = map[string]string{}
+
+// register adds the language's tokenizer under all its names.
+func register(name string, t tokenizer, other ...string) {
+	languages[name] = t
+	for _, alias := range other {
+		aliases[alias] = name
+	}
+}
+
+// Supports tells whether the language can be highlighted.
+func Supports(lang string) bool {
+	_, ok := languages[normalize(lang)]
+	return ok
+}
+
+// normalize returns the language's main name.
+func normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if name, ok := aliases[lang]; ok {
+		return name
+	}
+	return lang
+}
+
+// Highlight returns the code as html, where the tokens are wrapped in
+// spans with the highlight.js class names. Code of languages that are
+// not supported is only escaped, which is when false is returned.
+func Highlight(lang, code string) (string, bool) {
+	t, ok := languages[normalize(lang)]
+	if !ok {
+		return html.EscapeString(code), false
+	}
+	var output strings.Builder
+	for _, token := range t.tokenize(code) {
+		if len(token.class) < 1 {
+			output.WriteString(html.EscapeString(token.text))
+			continue
+		}
+		output.WriteString(`<span class="` + classPrefix + token.class + `">`)
+		output.WriteString(html.EscapeString(token.text))
+		output.WriteString(`</span>`)
+	}
+	return output.String(), true
+}
+
+// Theme returns the css of the theme, false if there's no such theme.
+func Theme(name string) (string, bool) {
+	css, err := themes.ReadFile("themes/" + name + ".css")
+	if err != nil {
+		return "", false
+	}
+	return string(css), true
+}
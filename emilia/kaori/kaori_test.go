@@ -0,0 +1,61 @@
+package kaori
+
+import (
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	type args struct {
+		lang string
+		code string
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want  string
+		want1 bool
+	}{
+		{"Go", args{"go", `func main() { return nil } // done`},
+			`<span class="hljs-keyword">func</span> <span class="hljs-title function_">main</span>() { ` +
+				`<span class="hljs-keyword">return</span> <span class="hljs-literal">nil</span> } ` +
+				`<span class="hljs-comment">// done</span>`, true},
+		{"Strings are escaped", args{"golang", `x := "<a href=\"#\">"`},
+			`x := <span class="hljs-string">&#34;&lt;a href=\&#34;#\&#34;&gt;&#34;</span>`, true},
+		{"Numbers", args{"python", `x = 0x1F + 1.5e-3`},
+			`x = <span class="hljs-number">0x1F</span> + <span class="hljs-number">1.5e-3</span>`, true},
+		{"Meta lines", args{"c", "  #include <stdio.h>\nint x;"},
+			"  <span class=\"hljs-meta\">#include &lt;stdio.h&gt;</span>\n<span class=\"hljs-type\">int</span> x;", true},
+		{"Shell variables", args{"sh", `echo "$HOME" ${PATH} $?`},
+			`<span class="hljs-built_in">echo</span> <span class="hljs-string">&#34;$HOME&#34;</span> ` +
+				`<span class="hljs-variable">${PATH}</span> <span class="hljs-variable">$?</span>`, true},
+		{"Lisp", args{"emacs-lisp", `(defun my-fun () :key)`},
+			`(<span class="hljs-keyword">defun</span> <span class="hljs-title function_">my-fun</span> () ` +
+				`<span class="hljs-symbol">:key</span>)`, true},
+		{"Json keys", args{"json", `{"a": true}`},
+			`{<span class="hljs-attr">&#34;a&#34;</span>: <span class="hljs-literal">true</span>}`, true},
+		{"Case insensitive", args{"sql", `SELECT * FROM t`},
+			`<span class="hljs-keyword">SELECT</span> * <span class="hljs-keyword">FROM</span> t`, true},
+		{"Rust macros", args{"rs", `println!("hi")`},
+			`<span class="hljs-built_in">println!</span>(<span class="hljs-string">&#34;hi&#34;</span>)`, true},
+		{"Diff", args{"diff", "@@ -1 +1 @@\n-old\n+new\n same"},
+			"<span class=\"hljs-meta\">@@ -1 +1 @@</span>\n<span class=\"hljs-deletion\">-old</span>\n" +
+				"<span class=\"hljs-addition\">+new</span>\n same", true},
+		{"Html", args{"html", `<a href="x" hidden>hi</a>`},
+			`<span class="hljs-tag">&lt;</span><span class="hljs-name">a</span> <span class="hljs-attr">href</span>=` +
+				`<span class="hljs-string">&#34;x&#34;</span> <span class="hljs-attr">hidden</span><span class="hljs-tag">&gt;</span>` +
+				`hi<span class="hljs-tag">&lt;/</span><span class="hljs-name">a</span><span class="hljs-tag">&gt;</span>`, true},
+		{"Unterminated", args{"go", `"never`}, `<span class="hljs-string">&#34;never</span>`, true},
+		{"Unknown", args{"brainfuck", `<+>`}, `&lt;+&gt;`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := Highlight(tt.args.lang, tt.args.code)
+			if got != tt.want {
+				t.Errorf("Highlight() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("Highlight() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
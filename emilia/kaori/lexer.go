@@ -0,0 +1,346 @@
+package kaori
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// words is a set of words.
+type words map[string]struct{}
+
+// newWords returns the set of the space-separated words.
+func newWords(what string) words {
+	result := words{}
+	for _, word := range strings.Fields(what) {
+		result[word] = struct{}{}
+	}
+	return result
+}
+
+// has tells whether the word is in the set.
+func (w words) has(word string) bool {
+	_, ok := w[word]
+	return ok
+}
+
+// lexer is a tokenizer of the languages that are made of comments,
+// strings, numbers and words, which is most of them.
+type lexer struct {
+	// lineComments start comments that go until the end of the line.
+	lineComments []string
+	// blockComments are the starts and ends of multiline comments.
+	blockComments [][2]string
+	// quotes are the strings' quotes, where the longer ones go first.
+	quotes []string
+	// rawQuotes are the quotes of the strings with no escapes.
+	rawQuotes []string
+
+	// keywords are the language's keywords.
+	keywords words
+	// types are the builtin types.
+	types words
+	// builtins are the builtin functions and objects.
+	builtins words
+	// literals are the builtin values, like true and false.
+	literals words
+	// titleAfter are the words that name what comes right after them,
+	// like functions after `func`.
+	titleAfter words
+
+	// identifierRunes are allowed in words besides letters, digits and '_'.
+	identifierRunes string
+	// caseInsensitive tells whether the words ignore the case.
+	caseInsensitive bool
+	// metaPrefix starts a meta line, like c's preprocessor directives.
+	metaPrefix string
+	// metaClass is the class of the meta lines, "meta" if empty.
+	metaClass string
+	// variablePrefix starts variables, like `$` in shell.
+	variablePrefix string
+	// symbolPrefix starts symbols, like `:` in lisp.
+	symbolPrefix string
+	// keySuffix makes the strings and words before it keys, like `:` in json.
+	keySuffix string
+	// macros tells whether words followed by `!` are macros, like in rust.
+	macros bool
+}
+
+// tokenize splits the code into tokens.
+func (l *lexer) tokenize(code string) []token {
+	tokens := make([]token, 0, len(code)/4)
+	plain := strings.Builder{}
+	emit := func(class, text string) {
+		if len(class) < 1 {
+			plain.WriteString(text)
+			return
+		}
+		if plain.Len() > 0 {
+			tokens = append(tokens, token{text: plain.String()})
+			plain.Reset()
+		}
+		tokens = append(tokens, token{class: class, text: text})
+	}
+
+	lineStart := true
+	// previousWord is the last word if nothing but spaces came after it.
+	previousWord := ""
+	for i := 0; i < len(code); {
+		rest := code[i:]
+		c := rest[0]
+
+		// Meta lines can only start the line.
+		if lineStart && len(l.metaPrefix) > 0 && strings.HasPrefix(rest, l.metaPrefix) {
+			end := lineEnd(rest)
+			emit(l.meta(), rest[:end])
+			i += end
+			continue
+		}
+		switch c {
+		case '\n':
+			lineStart = true
+			previousWord = ""
+			fallthrough
+		case ' ', '\t', '\r':
+			emit("", rest[:1])
+			i++
+			continue
+		}
+		lineStart = false
+
+		if prefix := hasAnyPrefix(rest, l.lineComments); len(prefix) > 0 {
+			end := lineEnd(rest)
+			emit("comment", rest[:end])
+			i += end
+			continue
+		}
+		if end := l.blockCommentEnd(rest); end > 0 {
+			emit("comment", rest[:end])
+			i += end
+			continue
+		}
+		if quote := hasAnyPrefix(rest, l.rawQuotes); len(quote) > 0 {
+			end := stringEnd(rest, quote, false)
+			emit(l.keyOr(rest[end:], "string"), rest[:end])
+			i += end
+			previousWord = ""
+			continue
+		}
+		if quote := hasAnyPrefix(rest, l.quotes); len(quote) > 0 {
+			end := stringEnd(rest, quote, true)
+			emit(l.keyOr(rest[end:], "string"), rest[:end])
+			i += end
+			previousWord = ""
+			continue
+		}
+		if isDigit(c) || (c == '.' && len(rest) > 1 && isDigit(rest[1])) {
+			end := numberEnd(rest)
+			emit("number", rest[:end])
+			i += end
+			previousWord = ""
+			continue
+		}
+		if len(l.variablePrefix) > 0 && strings.HasPrefix(rest, l.variablePrefix) {
+			if end := l.variableEnd(rest); end > 0 {
+				emit("variable", rest[:end])
+				i += end
+				previousWord = ""
+				continue
+			}
+		}
+		if len(l.symbolPrefix) > 0 && strings.HasPrefix(rest, l.symbolPrefix) {
+			if end := l.wordEnd(rest[len(l.symbolPrefix):]); end > 0 {
+				emit("symbol", rest[:len(l.symbolPrefix)+end])
+				i += len(l.symbolPrefix) + end
+				previousWord = ""
+				continue
+			}
+		}
+		if end := l.wordEnd(rest); end > 0 {
+			word := rest[:end]
+			// Macros take their exclamation mark with them.
+			if l.macros && strings.HasPrefix(rest[end:], "!") && !strings.HasPrefix(rest[end:], "!=") {
+				emit("built_in", rest[:end+1])
+				i += end + 1
+				previousWord = ""
+				continue
+			}
+			emit(l.classify(word, previousWord, rest[end:]), word)
+			previousWord = l.key(word)
+			i += end
+			continue
+		}
+
+		// Everything else, like operators and punctuation, is plain.
+		_, size := utf8.DecodeRuneInString(rest)
+		emit("", rest[:size])
+		i += size
+		previousWord = ""
+	}
+	if plain.Len() > 0 {
+		tokens = append(tokens, token{text: plain.String()})
+	}
+	return tokens
+}
+
+// classify returns the class of the word, where after is the code after it.
+func (l *lexer) classify(word, previousWord, after string) string {
+	key := l.key(word)
+	switch {
+	case l.keywords.has(key):
+		return "keyword"
+	case l.literals.has(key):
+		return "literal"
+	case l.types.has(key):
+		return "type"
+	case l.builtins.has(key):
+		return "built_in"
+	case l.titleAfter.has(previousWord):
+		return "title function_"
+	}
+	return l.keyOr(after, "")
+}
+
+// meta returns the class of the meta lines.
+func (l *lexer) meta() string {
+	if len(l.metaClass) < 1 {
+		return "meta"
+	}
+	return l.metaClass
+}
+
+// key returns the word as it's looked up.
+func (l *lexer) key(word string) string {
+	if l.caseInsensitive {
+		return strings.ToLower(word)
+	}
+	return word
+}
+
+// keyOr returns "attr" if the code after starts with the key suffix,
+// and the class otherwise.
+func (l *lexer) keyOr(after, class string) string {
+	if len(l.keySuffix) > 0 && strings.HasPrefix(strings.TrimLeft(after, " \t"), l.keySuffix) {
+		return "attr"
+	}
+	return class
+}
+
+// blockCommentEnd returns the end of the block comment that starts the
+// code, zero if there's none.
+func (l *lexer) blockCommentEnd(code string) int {
+	for _, comment := range l.blockComments {
+		if !strings.HasPrefix(code, comment[0]) {
+			continue
+		}
+		end := strings.Index(code[len(comment[0]):], comment[1])
+		if end < 0 {
+			return len(code)
+		}
+		return len(comment[0]) + end + len(comment[1])
+	}
+	return 0
+}
+
+// variableEnd returns the end of the variable that starts the code,
+// zero if it's not a variable.
+func (l *lexer) variableEnd(code string) int {
+	rest := code[len(l.variablePrefix):]
+	if strings.HasPrefix(rest, "{") {
+		if end := strings.IndexByte(rest, '}'); end > 0 {
+			return len(l.variablePrefix) + end + 1
+		}
+		return 0
+	}
+	if end := l.wordEnd(rest); end > 0 {
+		return len(l.variablePrefix) + end
+	}
+	// Special variables, like `$?` or `$1`.
+	if len(rest) > 0 && strings.IndexByte("?!#@*$0123456789-", rest[0]) >= 0 {
+		return len(l.variablePrefix) + 1
+	}
+	return 0
+}
+
+// wordEnd returns the end of the word that starts the code, zero if
+// the code doesn't start with a word.
+func (l *lexer) wordEnd(code string) int {
+	end := 0
+	for end < len(code) {
+		r, size := utf8.DecodeRuneInString(code[end:])
+		if !l.isIdentifier(r, end == 0) {
+			break
+		}
+		end += size
+	}
+	return end
+}
+
+// isIdentifier tells whether the rune can be in a word, where first
+// tells whether it's the first one.
+func (l *lexer) isIdentifier(r rune, first bool) bool {
+	if r == '_' || unicode.IsLetter(r) || strings.ContainsRune(l.identifierRunes, r) {
+		return true
+	}
+	return !first && unicode.IsDigit(r)
+}
+
+// stringEnd returns the end of the string that starts the code with
+// the quote, where backslashes escape if asked.
+func stringEnd(code, quote string, escapes bool) int {
+	i := len(quote)
+	for i < len(code) {
+		if escapes && code[i] == '\\' {
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(code[i:], quote) {
+			return i + len(quote)
+		}
+		i++
+	}
+	return len(code)
+}
+
+// numberEnd returns the end of the number that starts the code, which
+// takes hex, floats, exponents and suffixes like `10u32`.
+func numberEnd(code string) int {
+	i := 1
+	for i < len(code) {
+		c := code[i]
+		switch {
+		case isDigit(c) || isLetter(c) || c == '_':
+		case c == '.' && i+1 < len(code) && isDigit(code[i+1]):
+		case (c == '+' || c == '-') && (code[i-1] == 'e' || code[i-1] == 'E') &&
+			!strings.HasPrefix(code, "0x") && !strings.HasPrefix(code, "0X"):
+		default:
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+// lineEnd returns the end of the first line, without the newline.
+func lineEnd(code string) int {
+	if end := strings.IndexByte(code, '\n'); end >= 0 {
+		return end
+	}
+	return len(code)
+}
+
+// hasAnyPrefix returns the first prefix that starts the code.
+func hasAnyPrefix(code string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(code, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// isDigit tells whether the byte is an ascii digit.
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// isLetter tells whether the byte is an ascii letter.
+func isLetter(c byte) bool { return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') }
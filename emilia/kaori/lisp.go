@@ -0,0 +1,16 @@
+package kaori
+
+func init() {
+	register("lisp", &lexer{
+		lineComments: []string{";"},
+		quotes:       []string{`"`},
+		keywords: newWords(`defun defmacro defvar defcustom defconst defparameter defclass
+			defmethod defgeneric define lambda let let* flet labels if when unless cond case
+			progn prog1 setq setf and or not loop dolist dotimes while require provide
+			interactive quote function catch throw unwind-protect condition-case`),
+		literals:        newWords(`t nil`),
+		titleAfter:      newWords(`defun defmacro defvar defcustom defconst defparameter define`),
+		identifierRunes: "-+*/<>=!?&%.",
+		symbolPrefix:    ":",
+	}, "elisp", "emacs-lisp", "scheme", "clojure", "common-lisp")
+}
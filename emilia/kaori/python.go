@@ -0,0 +1,19 @@
+package kaori
+
+func init() {
+	register("python", &lexer{
+		lineComments: []string{"#"},
+		quotes:       []string{`"""`, `'''`, `"`, `'`},
+		keywords: newWords(`and as assert async await break case class continue def del
+			elif else except finally for from global if import in is lambda match
+			nonlocal not or pass raise return try while with yield`),
+		builtins: newWords(`abs all any bool bytes callable chr dict dir divmod enumerate
+			filter float format frozenset getattr hasattr hash help hex id input int
+			isinstance issubclass iter len list map max min next object open ord pow print
+			range repr reversed round set setattr slice sorted str sum super tuple type zip
+			self cls`),
+		literals:   newWords(`True False None`),
+		titleAfter: newWords(`def class`),
+		metaPrefix: "@",
+	}, "py", "python3")
+}
@@ -0,0 +1,19 @@
+package kaori
+
+func init() {
+	register("rust", &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		// Single quotes are left out, as they start lifetimes too.
+		quotes: []string{`"`},
+		keywords: newWords(`as async await break const continue crate dyn else enum extern
+			fn for if impl in let loop match mod move mut pub ref return self Self static
+			struct super trait type unsafe use where while`),
+		types: newWords(`bool char str i8 i16 i32 i64 i128 isize u8 u16 u32 u64 u128 usize
+			f32 f64 String Vec Option Result Box Rc Arc HashMap`),
+		literals:   newWords(`true false None Some Ok Err`),
+		titleAfter: newWords(`fn struct enum trait`),
+		metaPrefix: "#",
+		macros:     true,
+	}, "rs")
+}
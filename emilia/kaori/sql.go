@@ -0,0 +1,20 @@
+package kaori
+
+func init() {
+	register("sql", &lexer{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        []string{`'`, `"`},
+		keywords: newWords(`add all alter and as asc begin between by case check column commit
+			constraint create cross default delete desc distinct drop else end exists foreign
+			from full group having if in index inner insert intersect into is join key left
+			like limit not offset on or order outer primary references returning right
+			rollback select set table then transaction union unique update using values
+			view when where with`),
+		types: newWords(`bigint bit blob boolean char date datetime decimal double float int
+			integer json jsonb numeric real serial smallint text time timestamp uuid varchar`),
+		builtins:        newWords(`avg coalesce count lower max min now sum upper length substr round`),
+		literals:        newWords(`true false null`),
+		caseInsensitive: true,
+	}, "mysql", "postgres", "postgresql", "sqlite", "psql")
+}
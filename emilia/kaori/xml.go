@@ -0,0 +1,102 @@
+package kaori
+
+import (
+	"strings"
+)
+
+func init() {
+	register("xml", tokenizerFunc(tokenizeXml), "html", "xhtml", "svg", "rss", "atom", "plist")
+}
+
+// tokenizeXml colors the tags, their attributes and the comments, where
+// the text in between is left plain.
+func tokenizeXml(code string) []token {
+	tokens := make([]token, 0, len(code)/8)
+	for i := 0; i < len(code); {
+		rest := code[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := closingEnd(rest, "-->")
+			tokens = append(tokens, token{class: "comment", text: rest[:end]})
+			i += end
+		case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+			end := closingEnd(rest, ">")
+			tokens = append(tokens, token{class: "meta", text: rest[:end]})
+			i += end
+		case strings.HasPrefix(rest, "<") && len(rest) > 1 && (isLetter(rest[1]) || rest[1] == '/'):
+			tag, end := tokenizeTag(rest)
+			tokens = append(tokens, tag...)
+			i += end
+		default:
+			end := strings.IndexByte(rest[1:], '<') + 1
+			if end < 1 {
+				end = len(rest)
+			}
+			tokens = append(tokens, token{text: rest[:end]})
+			i += end
+		}
+	}
+	return tokens
+}
+
+// tokenizeTag colors the tag that starts the code and returns its end.
+func tokenizeTag(code string) ([]token, int) {
+	tokens := make([]token, 0, 8)
+	start := 1
+	if strings.HasPrefix(code, "</") {
+		start = 2
+	}
+	tokens = append(tokens, token{class: "tag", text: code[:start]})
+	end := start + nameEnd(code[start:])
+	tokens = append(tokens, token{class: "name", text: code[start:end]})
+	for i := end; i < len(code); {
+		rest := code[i:]
+		switch c := rest[0]; {
+		case strings.HasPrefix(rest, "/>"), c == '>':
+			size := 1
+			if c == '/' {
+				size = 2
+			}
+			tokens = append(tokens, token{class: "tag", text: rest[:size]})
+			return tokens, i + size
+		case c == '"' || c == '\'':
+			size := stringEnd(rest, string(c), false)
+			tokens = append(tokens, token{class: "string", text: rest[:size]})
+			i += size
+		case nameEnd(rest) > 0:
+			size := nameEnd(rest)
+			// The unquoted values are strings too.
+			class := "attr"
+			if strings.HasSuffix(strings.TrimRight(code[:i], " \t\n"), "=") {
+				class = "string"
+			}
+			tokens = append(tokens, token{class: class, text: rest[:size]})
+			i += size
+		default:
+			tokens = append(tokens, token{text: rest[:1]})
+			i++
+		}
+	}
+	return tokens, len(code)
+}
+
+// nameEnd returns the end of the tag or attribute name that starts the code.
+func nameEnd(code string) int {
+	end := 0
+	for end < len(code) {
+		c := code[end]
+		if !isLetter(c) && !isDigit(c) && strings.IndexByte("-_:.", c) < 0 {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+// closingEnd returns the end of the closing that ends the code.
+func closingEnd(code, closing string) int {
+	if end := strings.Index(code, closing); end >= 0 {
+		return end + len(closing)
+	}
+	return len(code)
+}
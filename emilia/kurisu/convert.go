@@ -0,0 +1,422 @@
+package kurisu
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// namespace is the xml namespace of MathML.
+const namespace = "http://www.w3.org/1998/Math/MathML"
+
+// Convert returns the TeX as MathML, where display makes it a block of
+// its own, or an error if the TeX uses what's not supported.
+func Convert(tex string, display bool) (string, error) {
+	p := &parser{tex: tex, display: display}
+	row, err := p.row("")
+	if err != nil {
+		return "", fmt.Errorf("converting %q: %v", tex, err)
+	}
+	mode := ""
+	if display {
+		mode = ` display="block"`
+	}
+	return `<math xmlns="` + namespace + `"` + mode + `><semantics><mrow>` + row +
+		`</mrow><annotation encoding="application/x-tex">` +
+		html.EscapeString(strings.TrimSpace(tex)) + `</annotation></semantics></math>`, nil
+}
+
+// parser reads the TeX and writes the MathML as it goes.
+type parser struct {
+	tex     string
+	pos     int
+	display bool
+}
+
+// atom is a parsed piece of math that can take scripts.
+type atom struct {
+	// ml is the MathML of the atom.
+	ml string
+	// limits tells whether the scripts go under and over in display math.
+	limits bool
+	// after goes after the atom and its scripts, like the space after sin.
+	after string
+}
+
+// row parses the atoms until the end or the closing, which is left for
+// the caller to take.
+func (p *parser) row(closing string) (string, error) {
+	b := strings.Builder{}
+	for {
+		p.skipSpaces()
+		if p.done() {
+			if len(closing) > 0 {
+				return "", fmt.Errorf("missing %s", closing)
+			}
+			return b.String(), nil
+		}
+		if len(closing) > 0 && p.closes(closing) {
+			return b.String(), nil
+		}
+		a, err := p.atom()
+		if err != nil {
+			return "", err
+		}
+		scripted, err := p.scripts(a)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(scripted)
+		b.WriteString(a.after)
+	}
+}
+
+// group parses the rest of a group after its opening brace.
+func (p *parser) group() (string, error) {
+	inner, err := p.row("}")
+	if err != nil {
+		return "", err
+	}
+	p.pos++
+	return "<mrow>" + inner + "</mrow>", nil
+}
+
+// atom parses the next atom.
+func (p *parser) atom() (atom, error) {
+	r, size := utf8.DecodeRuneInString(p.tex[p.pos:])
+	switch {
+	case r == '{':
+		p.pos++
+		inner, err := p.group()
+		return atom{ml: inner}, err
+	case r == '}':
+		return atom{}, fmt.Errorf("unexpected }")
+	case r == '^' || r == '_':
+		// Scripts with nothing before them, the scripts will take them.
+		return atom{ml: "<mrow></mrow>"}, nil
+	case r == '\\':
+		p.pos++
+		return p.command()
+	case isDigit(r) || (r == '.' && p.pos+1 < len(p.tex) && isDigit(rune(p.tex[p.pos+1]))):
+		start := p.pos
+		for p.pos < len(p.tex) && (isDigit(rune(p.tex[p.pos])) ||
+			(p.tex[p.pos] == '.' && p.pos+1 < len(p.tex) && isDigit(rune(p.tex[p.pos+1])))) {
+			p.pos++
+		}
+		return atom{ml: "<mn>" + p.tex[start:p.pos] + "</mn>"}, nil
+	case unicode.IsLetter(r):
+		p.pos += size
+		return atom{ml: "<mi>" + string(r) + "</mi>"}, nil
+	case r == '&':
+		// Alignment doesn't mean anything outside of tables.
+		p.pos++
+		return atom{}, nil
+	case r == '~':
+		p.pos++
+		return atom{ml: `<mtext>&#160;</mtext>`}, nil
+	case r == '\'':
+		p.pos++
+		return atom{ml: "<mo>′</mo>"}, nil
+	case r == '-':
+		// TeX's minus is longer than the hyphen.
+		p.pos++
+		return atom{ml: "<mo>−</mo>"}, nil
+	}
+	p.pos += size
+	return atom{ml: "<mo>" + html.EscapeString(string(r)) + "</mo>"}, nil
+}
+
+// scripts parses the scripts and primes that follow the atom.
+func (p *parser) scripts(a atom) (string, error) {
+	sub, sup := "", ""
+	for {
+		p.skipSpaces()
+		if p.done() {
+			break
+		}
+		c := p.tex[p.pos]
+		if c == '\'' {
+			p.pos++
+			sup += "<mo>′</mo>"
+			continue
+		}
+		if c != '^' && c != '_' {
+			break
+		}
+		p.pos++
+		arg, err := p.argument()
+		if err != nil {
+			return "", err
+		}
+		if c == '^' {
+			if len(sup) > 0 {
+				return "", fmt.Errorf("double superscript")
+			}
+			sup = arg
+			continue
+		}
+		if len(sub) > 0 {
+			return "", fmt.Errorf("double subscript")
+		}
+		sub = arg
+	}
+	under := a.limits && p.display
+	switch {
+	case len(sub) > 0 && len(sup) > 0 && under:
+		return "<munderover>" + a.ml + sub + sup + "</munderover>", nil
+	case len(sub) > 0 && len(sup) > 0:
+		return "<msubsup>" + a.ml + sub + sup + "</msubsup>", nil
+	case len(sub) > 0 && under:
+		return "<munder>" + a.ml + sub + "</munder>", nil
+	case len(sub) > 0:
+		return "<msub>" + a.ml + sub + "</msub>", nil
+	case len(sup) > 0 && under:
+		return "<mover>" + a.ml + sup + "</mover>", nil
+	case len(sup) > 0:
+		return "<msup>" + a.ml + sup + "</msup>", nil
+	}
+	return a.ml, nil
+}
+
+// argument parses the argument of a command or a script, which is
+// either a group or a single token, so `x^23` is x squared and a three.
+func (p *parser) argument() (string, error) {
+	p.skipSpaces()
+	if p.done() {
+		return "", fmt.Errorf("missing argument")
+	}
+	switch c := p.tex[p.pos]; {
+	case c == '{':
+		p.pos++
+		return p.group()
+	case c == '}' || c == '^' || c == '_':
+		return "", fmt.Errorf("missing argument before %c", c)
+	case isDigit(rune(c)):
+		p.pos++
+		return "<mn>" + string(c) + "</mn>", nil
+	}
+	a, err := p.atom()
+	return a.ml + a.after, err
+}
+
+// command parses the command after its backslash.
+func (p *parser) command() (atom, error) {
+	name := p.commandName()
+	switch name {
+	case "":
+		return atom{}, fmt.Errorf("lonely backslash")
+	case "frac", "dfrac", "tfrac":
+		numerator, denominator, err := p.twoArguments()
+		return atom{ml: "<mfrac>" + numerator + denominator + "</mfrac>"}, err
+	case "binom":
+		top, bottom, err := p.twoArguments()
+		return atom{ml: `<mrow><mo>(</mo><mfrac linethickness="0">` + top + bottom +
+			`</mfrac><mo>)</mo></mrow>`}, err
+	case "sqrt":
+		return p.sqrt()
+	case "left":
+		return p.fenced()
+	case "text", "textrm", "mbox", "textnormal":
+		text, err := p.rawArgument()
+		return atom{ml: "<mtext>" + html.EscapeString(text) + "</mtext>"}, err
+	case "mathrm", "operatorname":
+		text, err := p.rawArgument()
+		if err != nil || !isPlain(text) {
+			return atom{}, fmt.Errorf("unsupported \\%s{%s}", name, text)
+		}
+		return atom{ml: `<mi mathvariant="normal">` + html.EscapeString(text) + `</mi>`}, nil
+	case "\\":
+		// Line breaks only mean something in tables.
+		return atom{}, nil
+	case "label":
+		_, err := p.rawArgument()
+		return atom{}, err
+	}
+	if ignored[name] {
+		return atom{}, nil
+	}
+	if width, ok := spaces[name]; ok {
+		return atom{ml: `<mspace width="` + width + `"></mspace>`}, nil
+	}
+	if letter, ok := greek[name]; ok {
+		if unicode.IsUpper(rune(name[0])) {
+			return atom{ml: `<mi mathvariant="normal">` + letter + "</mi>"}, nil
+		}
+		return atom{ml: "<mi>" + letter + "</mi>"}, nil
+	}
+	if symbol, ok := identifiers[name]; ok {
+		return atom{ml: "<mi>" + symbol + "</mi>"}, nil
+	}
+	if symbol, ok := operators[name]; ok {
+		return atom{ml: "<mo>" + html.EscapeString(symbol) + "</mo>"}, nil
+	}
+	if operator, ok := largeOperators[name]; ok {
+		return atom{ml: "<mo>" + operator.symbol + "</mo>", limits: operator.limits}, nil
+	}
+	if limits, ok := functions[name]; ok {
+		return atom{ml: "<mi>" + name + "</mi>", limits: limits, after: `<mspace width="0.1667em"></mspace>`}, nil
+	}
+	if alphabet, ok := variants[name]; ok {
+		text, err := p.rawArgument()
+		if err != nil || !isPlain(text) {
+			return atom{}, fmt.Errorf("unsupported \\%s{%s}", name, text)
+		}
+		styled := strings.Map(alphabet.style, strings.ReplaceAll(text, " ", ""))
+		if utf8.RuneCountInString(styled) > 1 {
+			return atom{ml: `<mi mathvariant="normal">` + styled + "</mi>"}, nil
+		}
+		return atom{ml: "<mi>" + styled + "</mi>"}, nil
+	}
+	if accent, ok := accents[name]; ok {
+		arg, err := p.argument()
+		return atom{ml: `<mover accent="true">` + arg + "<mo>" + html.EscapeString(accent) + "</mo></mover>"}, err
+	}
+	return atom{}, fmt.Errorf("unsupported command \\%s", name)
+}
+
+// sqrt parses a square root, or any other with its optional index.
+func (p *parser) sqrt() (atom, error) {
+	p.skipSpaces()
+	if p.done() || p.tex[p.pos] != '[' {
+		arg, err := p.argument()
+		return atom{ml: "<msqrt>" + arg + "</msqrt>"}, err
+	}
+	p.pos++
+	index, err := p.row("]")
+	if err != nil {
+		return atom{}, err
+	}
+	p.pos++
+	arg, err := p.argument()
+	return atom{ml: "<mroot>" + arg + "<mrow>" + index + "</mrow></mroot>"}, err
+}
+
+// fenced parses the rest of `\left( ... \right)`.
+func (p *parser) fenced() (atom, error) {
+	open, err := p.delimiter()
+	if err != nil {
+		return atom{}, err
+	}
+	inner, err := p.row(`\right`)
+	if err != nil {
+		return atom{}, err
+	}
+	p.pos += len(`\right`)
+	closing, err := p.delimiter()
+	if err != nil {
+		return atom{}, err
+	}
+	return atom{ml: "<mrow>" + open + inner + closing + "</mrow>"}, nil
+}
+
+// delimiter parses the delimiter after `\left` or `\right`, where the
+// period is an invisible one.
+func (p *parser) delimiter() (string, error) {
+	p.skipSpaces()
+	if p.done() {
+		return "", fmt.Errorf("missing delimiter")
+	}
+	symbol := ""
+	switch r, size := utf8.DecodeRuneInString(p.tex[p.pos:]); r {
+	case '.':
+		p.pos++
+		return "", nil
+	case '\\':
+		p.pos++
+		name := p.commandName()
+		found, ok := operators[name]
+		if !ok {
+			return "", fmt.Errorf("unsupported delimiter \\%s", name)
+		}
+		symbol = found
+	default:
+		p.pos += size
+		symbol = string(r)
+	}
+	return `<mo stretchy="true">` + html.EscapeString(symbol) + "</mo>", nil
+}
+
+// twoArguments parses the two arguments, like of a fraction.
+func (p *parser) twoArguments() (string, string, error) {
+	first, err := p.argument()
+	if err != nil {
+		return "", "", err
+	}
+	second, err := p.argument()
+	return first, second, err
+}
+
+// rawArgument returns the text of the braced argument as it is.
+func (p *parser) rawArgument() (string, error) {
+	p.skipSpaces()
+	if p.done() || p.tex[p.pos] != '{' {
+		return "", fmt.Errorf("missing braced argument")
+	}
+	depth := 0
+	for i := p.pos; i < len(p.tex); i++ {
+		switch p.tex[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				text := p.tex[p.pos+1 : i]
+				p.pos = i + 1
+				return text, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("missing }")
+}
+
+// commandName returns the name of the command, which is either letters
+// or a single other character, like `\,`.
+func (p *parser) commandName() string {
+	start := p.pos
+	for p.pos < len(p.tex) && isLetter(p.tex[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start && p.pos < len(p.tex) {
+		_, size := utf8.DecodeRuneInString(p.tex[p.pos:])
+		p.pos += size
+	}
+	return p.tex[start:p.pos]
+}
+
+// closes tells whether the closing comes next, where commands have to
+// end there, so `\right` doesn't close at `\rightarrow`.
+func (p *parser) closes(closing string) bool {
+	if !strings.HasPrefix(p.tex[p.pos:], closing) {
+		return false
+	}
+	end := p.pos + len(closing)
+	return closing[0] != '\\' || end >= len(p.tex) || !isLetter(p.tex[end])
+}
+
+// skipSpaces skips the spaces, which don't mean anything in math.
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.tex) && strings.IndexByte(" \t\r\n", p.tex[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// done tells whether everything has been read.
+func (p *parser) done() bool { return p.pos >= len(p.tex) }
+
+// isPlain tells whether the text has nothing but letters, digits and spaces.
+func isPlain(text string) bool {
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' {
+			return false
+		}
+	}
+	return len(text) > 0
+}
+
+// isDigit tells whether the rune is an ascii digit.
+func isDigit(r rune) bool { return '0' <= r && r <= '9' }
+
+// isLetter tells whether the byte is an ascii letter.
+func isLetter(c byte) bool { return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') }
@@ -0,0 +1,109 @@
+package kurisu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Math is a piece of math found in the text.
+type Math struct {
+	// Start is where the math starts in the text, with its delimiters.
+	Start int
+	// End is where the math ends in the text, with its delimiters.
+	End int
+	// TeX is the math without its delimiters.
+	TeX string
+	// Display tells whether the math is a block of its own.
+	Display bool
+	// Environment is the name of the `\begin` environment, if any.
+	Environment string
+}
+
+// FindAllOutside returns the math of the text like `FindAll`, where the
+// text inside of the skipped `[start, end)` ranges is never math.
+func FindAllOutside(text string, skipped [][]int) []Math {
+	if len(skipped) < 1 {
+		return FindAll(text)
+	}
+	// Blank out the skipped text, so that its dollars don't pair up
+	// with the ones outside of it.
+	masked := []byte(text)
+	for _, span := range skipped {
+		for i := span[0]; i < span[1]; i++ {
+			masked[i] = ' '
+		}
+	}
+	found := FindAll(string(masked))
+	result := make([]Math, 0, len(found))
+	for _, math := range found {
+		if !overlaps(math, skipped) {
+			result = append(result, math)
+		}
+	}
+	return result
+}
+
+// overlaps tells whether the math overlaps any of the ranges.
+func overlaps(math Math, ranges [][]int) bool {
+	for _, span := range ranges {
+		if math.Start < span[1] && span[0] < math.End {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAll returns the inline `$...$`, the display `$$...$$`, and the
+// `\begin{...}` environments of the text in order.
+func FindAll(text string) []Math {
+	found := make([]Math, 0, 2)
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case strings.HasPrefix(rest, `\$`):
+			// Escaped dollars are just dollars.
+			i += 2
+			continue
+		case strings.HasPrefix(rest, "$$"):
+			if end := strings.Index(rest[2:], "$$"); end > 0 {
+				found = append(found, Math{Start: i, End: i + end + 4, TeX: rest[2 : end+2], Display: true})
+				i += end + 4
+				continue
+			}
+		case strings.HasPrefix(rest, `\begin{`):
+			name, _, ok := strings.Cut(rest[len(`\begin{`):], "}")
+			closing := `\end{` + name + `}`
+			if end := strings.Index(rest, closing); ok && end > 0 {
+				found = append(found, Math{
+					Start:       i,
+					End:         i + end + len(closing),
+					TeX:         rest[len(`\begin{`)+len(name)+1 : end],
+					Display:     true,
+					Environment: name,
+				})
+				i += end + len(closing)
+				continue
+			}
+		case rest[0] == '$':
+			// Inline math stays on one line, same as `yunyun.MathRegexp`.
+			end := strings.IndexAny(rest[1:], "$\n")
+			if end > 0 && rest[1+end] == '$' {
+				found = append(found, Math{Start: i, End: i + end + 2, TeX: rest[1 : end+1]})
+				i += end + 2
+				continue
+			}
+		}
+		i++
+	}
+	return found
+}
+
+// MathML returns the math as MathML, or an error if it uses what's not
+// supported, which includes environments other than equations.
+func (m Math) MathML() (string, error) {
+	switch m.Environment {
+	case "", "equation", "equation*":
+		return Convert(m.TeX, m.Display)
+	}
+	return "", fmt.Errorf("unsupported environment %s", m.Environment)
+}
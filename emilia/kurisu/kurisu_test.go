@@ -0,0 +1,105 @@
+package kurisu
+
+import (
+	"html"
+	"reflect"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		tex     string
+		want    string
+		wantErr bool
+	}{
+		{"Letters and numbers", `x + 3.14`, `<mi>x</mi><mo>+</mo><mn>3.14</mn>`, false},
+		{"Scripts", `x_i^2`, `<msubsup><mi>x</mi><mi>i</mi><mn>2</mn></msubsup>`, false},
+		{"Single token scripts", `x^23`, `<msup><mi>x</mi><mn>2</mn></msup><mn>3</mn>`, false},
+		{"Fractions", `\frac{a}{2}`, `<mfrac><mrow><mi>a</mi></mrow><mrow><mn>2</mn></mrow></mfrac>`, false},
+		{"Roots", `\sqrt[3]{x}`, `<mroot><mrow><mi>x</mi></mrow><mrow><mn>3</mn></mrow></mroot>`, false},
+		{"Greek", `\alpha\Omega`, `<mi>α</mi><mi mathvariant="normal">Ω</mi>`, false},
+		{"Minus", `a-b`, `<mi>a</mi><mo>−</mo><mi>b</mi>`, false},
+		{"Relations are escaped", `a<b`, `<mi>a</mi><mo>&lt;</mo><mi>b</mi>`, false},
+		{"Fences", `\left( x \right)`, `<mrow><mo stretchy="true">(</mo><mi>x</mi><mo stretchy="true">)</mo></mrow>`, false},
+		{"Arrows are not fences", `\left. a \rightarrow b \right|`,
+			`<mrow><mi>a</mi><mo>→</mo><mi>b</mi><mo stretchy="true">|</mo></mrow>`, false},
+		{"Functions", `\sin x`, `<mi>sin</mi><mspace width="0.1667em"></mspace><mi>x</mi>`, false},
+		{"Double struck", `\mathbb{R}`, `<mi>ℝ</mi>`, false},
+		{"Text", `\text{if } x`, `<mtext>if </mtext><mi>x</mi>`, false},
+		{"Primes", `f'`, `<msup><mi>f</mi><mo>′</mo></msup>`, false},
+		{"Unknown commands", `\frobnicate`, ``, true},
+		{"Unclosed groups", `\frac{a`, ``, true},
+		{"Double superscripts", `x^2^3`, ``, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.tex, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			want := ``
+			if !tt.wantErr {
+				want = `<math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mrow>` + tt.want +
+					`</mrow><annotation encoding="application/x-tex">` + html.EscapeString(tt.tex) + `</annotation></semantics></math>`
+			}
+			if got != want {
+				t.Errorf("Convert() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestConvertDisplay(t *testing.T) {
+	got, err := Convert(`\sum_{i=1}^n i`, true)
+	want := `<math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><semantics><mrow>` +
+		`<munderover><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></munderover><mi>i</mi>` +
+		`</mrow><annotation encoding="application/x-tex">\sum_{i=1}^n i</annotation></semantics></math>`
+	if err != nil || got != want {
+		t.Errorf("Convert() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Math
+	}{
+		{"Inline", `so $x$ is`, []Math{{Start: 3, End: 6, TeX: "x"}}},
+		{"Display", `$$x$$ and $y$`, []Math{{Start: 0, End: 5, TeX: "x", Display: true}, {Start: 10, End: 13, TeX: "y"}}},
+		{"Environments", `\begin{equation}x\end{equation}`,
+			[]Math{{Start: 0, End: 31, TeX: "x", Display: true, Environment: "equation"}}},
+		{"Escaped dollars", `\$5 and \$6`, []Math{}},
+		{"Inline is one line", "$5\nand $6", []Math{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindAll(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAllOutside(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		skipped [][]int
+		want    []Math
+	}{
+		{"Nothing skipped", `$x$`, nil, []Math{{Start: 0, End: 3, TeX: "x"}}},
+		{"Skipped math", `=$x$= and $y$`, [][]int{{0, 5}}, []Math{{Start: 10, End: 13, TeX: "y"}}},
+		{"Skipped dollars don't pair", `=$5= and $y$`, [][]int{{0, 4}}, []Math{{Start: 9, End: 12, TeX: "y"}}},
+		{"Math around skipped text", `$a =b= c$`, [][]int{{3, 6}}, []Math{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindAllOutside(tt.text, tt.skipped); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAllOutside() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
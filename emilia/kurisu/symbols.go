@@ -0,0 +1,140 @@
+package kurisu
+
+// greek are the greek letters, the uppercase ones are upright.
+var greek = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ",
+	"varepsilon": "ε", "zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ",
+	"iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ",
+	"omicron": "ο", "pi": "π", "varpi": "ϖ", "rho": "ρ", "varrho": "ϱ",
+	"sigma": "σ", "varsigma": "ς", "tau": "τ", "upsilon": "υ", "phi": "ϕ",
+	"varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ",
+	"Pi": "Π", "Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ",
+	"Omega": "Ω",
+}
+
+// identifiers are the symbols that act as letters.
+var identifiers = map[string]string{
+	"infty": "∞", "partial": "∂", "nabla": "∇", "emptyset": "∅",
+	"varnothing": "∅", "hbar": "ℏ", "ell": "ℓ", "Re": "ℜ", "Im": "ℑ",
+	"aleph": "ℵ", "wp": "℘",
+}
+
+// operators are the symbols that act as operators, relations,
+// arrows and punctuation.
+var operators = map[string]string{
+	"cdot": "⋅", "times": "×", "div": "÷", "pm": "±", "mp": "∓", "ast": "∗",
+	"star": "⋆", "circ": "∘", "bullet": "∙", "oplus": "⊕", "ominus": "⊖",
+	"otimes": "⊗", "odot": "⊙", "setminus": "∖", "cup": "∪", "cap": "∩",
+	"wedge": "∧", "land": "∧", "vee": "∨", "lor": "∨", "neg": "¬", "lnot": "¬",
+
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠",
+	"ll": "≪", "gg": "≫", "approx": "≈", "equiv": "≡", "sim": "∼",
+	"simeq": "≃", "cong": "≅", "propto": "∝", "in": "∈", "notin": "∉",
+	"ni": "∋", "subset": "⊂", "subseteq": "⊆", "supset": "⊃", "supseteq": "⊇",
+	"perp": "⊥", "parallel": "∥", "mid": "∣", "forall": "∀", "exists": "∃",
+	"nexists": "∄", "angle": "∠", "triangle": "△", "prime": "′",
+	"vdash": "⊢", "models": "⊨", "top": "⊤", "bot": "⊥",
+
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←",
+	"leftrightarrow": "↔", "Rightarrow": "⇒", "Leftarrow": "⇐",
+	"Leftrightarrow": "⇔", "implies": "⟹", "impliedby": "⟸", "iff": "⟺",
+	"mapsto": "↦", "uparrow": "↑", "downarrow": "↓", "longrightarrow": "⟶",
+	"longleftarrow": "⟵", "hookrightarrow": "↪",
+
+	"ldots": "…", "dots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱",
+	"colon": ":", "langle": "⟨", "rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋",
+	"lceil": "⌈", "rceil": "⌉", "vert": "|", "Vert": "‖", "lvert": "|",
+	"rvert": "|", "lVert": "‖", "rVert": "‖",
+	"{": "{", "}": "}", "|": "‖", "%": "%", "$": "$", "&": "&", "#": "#", "_": "_",
+}
+
+// largeOperators are the operators that grow in display math, where
+// the limits go under and over those that have them.
+var largeOperators = map[string]struct {
+	symbol string
+	limits bool
+}{
+	"sum": {"∑", true}, "prod": {"∏", true}, "coprod": {"∐", true},
+	"bigcup": {"⋃", true}, "bigcap": {"⋂", true}, "bigoplus": {"⨁", true},
+	"bigotimes": {"⨂", true}, "bigvee": {"⋁", true}, "bigwedge": {"⋀", true},
+	"int": {"∫", false}, "iint": {"∬", false}, "iiint": {"∭", false},
+	"oint": {"∮", false},
+}
+
+// functions are the named functions, like sin, where the limits go
+// under those that have them in display math.
+var functions = map[string]bool{
+	"sin": false, "cos": false, "tan": false, "cot": false, "sec": false,
+	"csc": false, "arcsin": false, "arccos": false, "arctan": false,
+	"sinh": false, "cosh": false, "tanh": false, "coth": false, "log": false,
+	"ln": false, "lg": false, "exp": false, "arg": false, "deg": false,
+	"dim": false, "hom": false, "ker": false,
+
+	"lim": true, "liminf": true, "limsup": true, "max": true, "min": true,
+	"sup": true, "inf": true, "det": true, "gcd": true, "Pr": true,
+}
+
+// alphabet is where a style's letters and digits start in the unicode
+// math alphanumerics, zero if the style doesn't have them.
+type alphabet struct {
+	upper, lower, digits rune
+	// exceptions are the letters that unicode had before the rest.
+	exceptions map[rune]rune
+}
+
+// variants are the font commands with their alphabets, as browsers
+// only know the "normal" mathvariant.
+var variants = map[string]alphabet{
+	"mathbf":     {upper: 0x1D400, lower: 0x1D41A, digits: 0x1D7CE},
+	"mathit":     {upper: 0x1D434, lower: 0x1D44E, exceptions: map[rune]rune{'h': 'ℎ'}},
+	"boldsymbol": {upper: 0x1D468, lower: 0x1D482, digits: 0x1D7CE},
+	"mathcal": {upper: 0x1D49C, lower: 0x1D4B6, exceptions: map[rune]rune{
+		'B': 'ℬ', 'E': 'ℰ', 'F': 'ℱ', 'H': 'ℋ', 'I': 'ℐ', 'L': 'ℒ', 'M': 'ℳ',
+		'R': 'ℛ', 'e': 'ℯ', 'g': 'ℊ', 'o': 'ℴ',
+	}},
+	"mathfrak": {upper: 0x1D504, lower: 0x1D51E, exceptions: map[rune]rune{
+		'C': 'ℭ', 'H': 'ℌ', 'I': 'ℑ', 'R': 'ℜ', 'Z': 'ℨ',
+	}},
+	"mathbb": {upper: 0x1D538, lower: 0x1D552, digits: 0x1D7D8, exceptions: map[rune]rune{
+		'C': 'ℂ', 'H': 'ℍ', 'N': 'ℕ', 'P': 'ℙ', 'Q': 'ℚ', 'R': 'ℝ', 'Z': 'ℤ',
+	}},
+	"mathsf": {upper: 0x1D5A0, lower: 0x1D5BA, digits: 0x1D7E2},
+	"mathtt": {upper: 0x1D670, lower: 0x1D68A, digits: 0x1D7F6},
+}
+
+// style returns the rune in the alphabet, or itself if it has none.
+func (a alphabet) style(r rune) rune {
+	if styled, ok := a.exceptions[r]; ok {
+		return styled
+	}
+	switch {
+	case 'A' <= r && r <= 'Z' && a.upper > 0:
+		return a.upper + r - 'A'
+	case 'a' <= r && r <= 'z' && a.lower > 0:
+		return a.lower + r - 'a'
+	case '0' <= r && r <= '9' && a.digits > 0:
+		return a.digits + r - '0'
+	}
+	return r
+}
+
+// accents are the accents with the symbols that go over the argument.
+var accents = map[string]string{
+	"hat": "^", "widehat": "^", "bar": "¯", "overline": "¯", "vec": "→",
+	"overrightarrow": "→", "dot": "˙", "ddot": "¨", "tilde": "~",
+	"widetilde": "~", "check": "ˇ", "breve": "˘", "acute": "´", "grave": "`",
+}
+
+// spaces are the spacing commands with their widths.
+var spaces = map[string]string{
+	",": "0.1667em", ":": "0.2222em", ">": "0.2222em", ";": "0.2778em",
+	" ": "0.25em", "quad": "1em", "qquad": "2em", "!": "-0.1667em",
+}
+
+// ignored are the commands that don't change how the math looks here.
+var ignored = map[string]bool{
+	"displaystyle": true, "textstyle": true, "nonumber": true, "notag": true,
+	"limits": true, "nolimits": true,
+}
@@ -25,7 +25,7 @@ func WithFootnotes() yunyun.PageOption {
 			}
 
 			// Footnotes can also appear in lists
-			if c.IsList() {
+			if c.IsList() || c.IsListNumbered() {
 				for i := 0; i < len(c.List); i++ {
 					c.List[i].Text = findFootnotes(c.List[i].Text, &footnotes)
 				}
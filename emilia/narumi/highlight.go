@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/kaori"
 	"github.com/thecsw/darkness/yunyun"
 )
 
@@ -18,7 +19,7 @@ const (
 func WithSyntaxHighlighting(conf *alpha.DarknessConfig) yunyun.PageOption {
 	return func(page *yunyun.Page) {
 		// If Emilia disabled the syntax highlighting, don't even bother.
-		if !conf.Website.SyntaxHighlighting {
+		if !conf.Website.SyntaxHighlighting && !conf.Website.SyntaxHighlightingStatic {
 			return
 		}
 		// Find all the code blocks.
@@ -28,6 +29,13 @@ func WithSyntaxHighlighting(conf *alpha.DarknessConfig) yunyun.PageOption {
 			return
 		}
 
+		// The code is colored when exported, only the colors are needed.
+		if conf.Website.SyntaxHighlightingStatic {
+			theme, _ := kaori.Theme(conf.Website.SyntaxHighlightingStaticTheme)
+			page.Stylesheets = append(page.Stylesheets, "<style>"+theme+"</style>")
+			return
+		}
+
 		// Add the basic processing scripts.
 		page.Stylesheets = append(page.Stylesheets,
 			fmt.Sprintf(highlightJsTheme, conf.Runtime.Join(conf.Website.SyntaxHighlightingTheme)))
@@ -23,6 +23,28 @@ func ConvertHoloscene(HEtime string) (time.Time, bool) {
 	return getHoloscene(extractHoloscene(HEtime))
 }
 
+// ValidateHoloscene returns an error if the Holoscene time (127; 12022 H.E.)
+// is malformed or points at a day or time that doesn't exist.
+func ValidateHoloscene(HEtime string) error {
+	dayS, yearS, hourS, minuteS := extractHoloscene(HEtime)
+	if len(dayS) < 1 {
+		return fmt.Errorf("%q is not a holoscene date", HEtime)
+	}
+	day, year, hour, minute := a0(dayS), a0(yearS), a0(hourS), a0(minuteS)
+	if year <= 10000 {
+		return fmt.Errorf("year %d is before the holoscene era", year)
+	}
+	// Leap years have one more day.
+	daysInYear := time.Date(year-10000, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	if day < 1 || day > daysInYear {
+		return fmt.Errorf("day %d is not within 1 and %d", day, daysInYear)
+	}
+	if hour > 23 || minute > 59 {
+		return fmt.Errorf("time %s%s is not a valid time of day", hourS, minuteS)
+	}
+	return nil
+}
+
 // extractHoloscene extracts the holoscene time from a string, the return
 // values are day, year, hour, minute.
 func extractHoloscene(data string) (string, string, string, string) {
@@ -104,3 +104,26 @@ func Test_getHoloscene(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateHoloscene(t *testing.T) {
+	tests := []struct {
+		name    string
+		HEtime  string
+		wantErr bool
+	}{
+		{"Test 1", "127; 12022 H.E. 1234", false},
+		{"Test 2", "366; 12024 H.E.", false},
+		{"Test 3", "366; 12023 H.E.", true},
+		{"Test 4", "0; 12023 H.E.", true},
+		{"Test 5", "127; 12022 H.E. 2460", true},
+		{"Test 6", "127; 2022 H.E.", true},
+		{"Test 7", "hello", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateHoloscene(tt.HEtime); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHoloscene() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
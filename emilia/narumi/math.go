@@ -1,8 +1,10 @@
 package narumi
 
 import (
-	"strings"
+	"fmt"
 
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/kurisu"
 	"github.com/thecsw/darkness/yunyun"
 	"github.com/thecsw/gana"
 )
@@ -12,6 +14,8 @@ const (
 	katexLocalJS         yunyun.RelativePathFile = `scripts/katex/katex.min.js`
 	katexLocalAutoRender yunyun.RelativePathFile = `scripts/katex/auto-render.min.js`
 
+	katexRemote = `https://cdn.jsdelivr.net/npm/katex@0.15.3/dist/`
+
 	katexJs = `
 <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/katex@0.15.3/dist/katex.min.css" integrity="sha384-KiWOvVjnN8qwAZbuQyWDIbfCLFhLXNETzBQjA/92pIowpC0d2O3nppDGQVgwd2nB" crossorigin="anonymous">
 <!-- The loading of KaTeX is deferred to speed up page rendering -->
@@ -19,7 +23,19 @@ const (
 <!-- To automatically render math in text elements, include the auto-render extension: -->
 <script defer src="https://cdn.jsdelivr.net/npm/katex@0.15.3/dist/contrib/auto-render.min.js" integrity="sha384-+XBljXPPiv+OzfbB3cVmLHf4hdUFHlWNZN5spNQ7rmHTXpd7WvJum6fIACpNNfIR" crossorigin="anonymous"
         onload="renderMathInElement(document.body);"></script>
-<script>
+` + katexRender
+
+	// katexLocalJs is katexJs with the vendored copies of KaTeX.
+	katexLocalJs = `
+<link rel="stylesheet" href="%s">
+<!-- The loading of KaTeX is deferred to speed up page rendering -->
+<script defer src="%s"></script>
+<!-- To automatically render math in text elements, include the auto-render extension: -->
+<script defer src="%s"
+        onload="renderMathInElement(document.body);"></script>
+` + katexRender
+
+	katexRender = `<script>
     document.addEventListener("DOMContentLoaded", function() {
         renderMathInElement(document.body, {
           // customised options
@@ -45,22 +61,39 @@ const (
     });
 </script>
 `
+)
 
-	mathJs = katexJs
+var (
+	// katexAssets are the files of KaTeX that the offline websites vendor.
+	katexAssets = []VendoredAsset{
+		{Local: katexLocalCSS, Remote: katexRemote + "katex.min.css"},
+		{Local: katexLocalJS, Remote: katexRemote + "katex.min.js"},
+		{Local: katexLocalAutoRender, Remote: katexRemote + "contrib/auto-render.min.js"},
+	}
 )
 
-// WithMathSupport adds math support to the page using javascript injection
-func WithMathSupport() yunyun.PageOption {
+// WithMathSupport adds math support to the page using javascript injection,
+// which is only needed for the math that can't be exported as MathML.
+func WithMathSupport(conf *alpha.DarknessConfig) yunyun.PageOption {
 	return func(page *yunyun.Page) {
 		// If we found math-related tags or forced by user
 		if hasMathEquations(page) && !page.Accoutrement.Math.IsDisabled() {
-			page.Scripts = append(page.Scripts, mathJs)
+			page.Scripts = append(page.Scripts, mathJs(conf))
 		}
 	}
 }
 
-// hasMathEquations returns true if the page has any math equations and
-// returns false otherwise.
+// mathJs returns the KaTeX scripts, which are local for offline websites.
+func mathJs(conf *alpha.DarknessConfig) string {
+	if !conf.Website.Offline {
+		return katexJs
+	}
+	return fmt.Sprintf(katexLocalJs, VendoredPath(conf, katexLocalCSS),
+		VendoredPath(conf, katexLocalJS), VendoredPath(conf, katexLocalAutoRender))
+}
+
+// hasMathEquations returns true if the page has any math equations that
+// can't be converted to MathML and returns false otherwise.
 func hasMathEquations(page *yunyun.Page) bool {
 	return gana.Anyf(hasEquationInContent, page.Contents)
 }
@@ -75,21 +108,16 @@ func hasEquationInContent(content *yunyun.Content) bool {
 // hasEquationInParagraph returns true if the content is a paragraph
 // AND there is some math in there.
 func hasEquationInParagraph(content *yunyun.Content) bool {
-	if content.IsParagraph() && (strings.Contains(content.Paragraph, `\begin`) ||
-		yunyun.MathRegexp.MatchString(content.Paragraph)) {
-		return true
-	}
-	// If none of the above worked, give up on this paragraph.
-	return false
+	return content.IsParagraph() && hasUnconvertibleMath(content.Paragraph)
 }
 
 // hasEquationInList returns true if the list has math equations.
 func hasEquationInList(content *yunyun.Content) bool {
-	if !content.IsList() {
+	if !content.IsList() && !content.IsListNumbered() {
 		return false
 	}
 	return gana.Anyf(
-		yunyun.MathRegexp.MatchString,
+		hasUnconvertibleMath,
 		gana.Map(func(t yunyun.ListItem) string { return t.Text }, content.List),
 	)
 }
@@ -99,5 +127,14 @@ func hasEquationsInHeading(content *yunyun.Content) bool {
 	if !content.IsHeading() {
 		return false
 	}
-	return yunyun.MathRegexp.MatchString(content.Heading)
+	return hasUnconvertibleMath(content.Heading)
+}
+
+// hasUnconvertibleMath returns true if the text has math that can't be
+// converted to MathML, which KaTeX still has to render.
+func hasUnconvertibleMath(text string) bool {
+	return gana.Anyf(func(math kurisu.Math) bool {
+		_, err := math.MathML()
+		return err != nil
+	}, kurisu.FindAllOutside(text, yunyun.MathlessRanges(text)))
 }
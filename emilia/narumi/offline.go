@@ -0,0 +1,40 @@
+package narumi
+
+import (
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// VendoredAsset is a remote file that the pages use, which offline
+// websites keep a copy of in their vendor directory.
+type VendoredAsset struct {
+	// Local is where the copy is, relative to the vendor directory.
+	Local yunyun.RelativePathFile
+	// Remote is the url of the original.
+	Remote string
+}
+
+// TimeScript is the time script that every page loads.
+var TimeScript = VendoredAsset{
+	Local:  `scripts/time.js`,
+	Remote: `https://sandyuraz.com/scripts/time.js`,
+}
+
+// VendoredAssets returns all the remote files that offline websites vendor.
+func VendoredAssets() []VendoredAsset {
+	return append([]VendoredAsset{TimeScript}, katexAssets...)
+}
+
+// VendoredPath returns the url of the vendored copy of the local path.
+func VendoredPath(conf *alpha.DarknessConfig, local yunyun.RelativePathFile) yunyun.FullPathFile {
+	return conf.Runtime.Join(yunyun.JoinRelativePaths(conf.Project.DarknessVendorDirectory, local))
+}
+
+// Url returns the url of the asset, which is the vendored copy if the
+// website is offline.
+func (asset VendoredAsset) Url(conf *alpha.DarknessConfig) string {
+	if !conf.Website.Offline {
+		return asset.Remote
+	}
+	return string(VendoredPath(conf, asset.Local))
+}
@@ -22,6 +22,16 @@ const (
 	// DefaultPreviewDirectory is the name of the dir where all gallery previews are stored.
 	DefaultPreviewDirectory yunyun.RelativePathDir = "darkness_gallery_previews"
 
+	// SearchIndexFile is where the search index is written.
+	SearchIndexFile yunyun.RelativePathFile = "search/index.json"
+	// SearchScriptFile is where the search widget is written.
+	SearchScriptFile yunyun.RelativePathFile = "search/search.js"
+	// SearchShardsDirectory is where the shards of the search index are written.
+	SearchShardsDirectory yunyun.RelativePathDir = "search/shards"
+
+	// ThemeDirectory is where the website's own html templates are.
+	ThemeDirectory yunyun.RelativePathDir = "theme"
+
 	PagePreviewWidth  = 1200
 	PagePreviewHeight = 700
 )
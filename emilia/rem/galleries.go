@@ -78,3 +78,22 @@ func GalleryItemToImage(conf *alpha.DarknessConfig, item GalleryItem, authority,
 	// If it's a remote file, then ask Emilia to try and fetch it.
 	return reze.DownloadImage(string(item.Item), authority, prefix, string(galleryItemHash(item)))
 }
+
+// MissingGalleryImages returns the local gallery items of the page
+// that don't exist on disk.
+func MissingGalleryImages(conf *alpha.DarknessConfig, page *yunyun.Page) []GalleryItem {
+	missing := make([]GalleryItem, 0)
+	for _, gallery := range page.Contents.Galleries() {
+		for _, listItem := range gallery.List {
+			item := NewGalleryItem(page, gallery, listItem.Text)
+			if item.IsExternal {
+				continue
+			}
+			file := conf.Runtime.WorkDir.Join(yunyun.JoinRelativePaths(item.Path, item.Item))
+			if exists, _ := rei.FileExists(string(file)); !exists {
+				missing = append(missing, item)
+			}
+		}
+	}
+	return missing
+}
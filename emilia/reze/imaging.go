@@ -39,14 +39,30 @@ var (
 // DownloadImage attempts to download an image and returns it
 // with any fatal errors (if occured).
 func DownloadImage(link string, authority, prefix, name string) (image.Image, error) {
+	data, err := Download(link, authority, prefix, name)
+	if err != nil {
+		return nil, fmt.Errorf("downloading image: %v", err)
+	}
+
+	// Attempt to decode.
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, fmt.Errorf("decoding downloaded image: %v", err)
+	}
+
+	return img, nil
+}
+
+// Download downloads the link with a progress bar and returns its data.
+func Download(link string, authority, prefix, name string) ([]byte, error) {
 	resp, cancel, err := haruhi.URL(link).Client(vendorClient).Response()
 	defer cancel()
 	if err != nil {
-		return nil, fmt.Errorf("downloading image: %v", err)
+		return nil, fmt.Errorf("requesting: %v", err)
 	}
 	// If we got not found or server issue, bail.
 	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("downloading image got bad status %d", resp.StatusCode)
+		return nil, fmt.Errorf("got bad status %d", resp.StatusCode)
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -58,14 +74,7 @@ func DownloadImage(link string, authority, prefix, name string) (image.Image, er
 	buf := new(bytes.Buffer)
 	bar := ProgressBar(resp.ContentLength, authority, prefix, "Downloading", name)
 	if _, err := io.Copy(io.MultiWriter(buf, bar), resp.Body); err != nil && err != io.EOF {
-		return nil, fmt.Errorf("reading downloaded image data: %v", err)
-	}
-
-	// Attempt to decode.
-	img, err := imaging.Decode(buf, imaging.AutoOrientation(true))
-	if err != nil {
-		return nil, fmt.Errorf("decoding downloaded image: %v", err)
+		return nil, fmt.Errorf("reading downloaded data: %v", err)
 	}
-
-	return img, nil
+	return buf.Bytes(), nil
 }
@@ -0,0 +1,32 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// backlinks returns the "Linked from" section of the pages that link
+// to this page, empty if there are none.
+func (e *state) backlinks() string {
+	if len(e.page.Backlinks) < 1 {
+		return ""
+	}
+	items := make([]string, 0, len(e.page.Backlinks))
+	for _, backlink := range e.page.Backlinks {
+		items = append(items, fmt.Sprintf(`<li><a href="%s">%s</a></li>`,
+			e.conf.Runtime.Join(yunyun.RelativePathFile(backlink.Location)),
+			e.title(backlink.Title),
+		))
+	}
+	return fmt.Sprintf(`
+<div id="backlinks" class="backlinks">
+<hr>
+<div class="title">Linked from</div>
+<ul>
+%s
+</ul>
+</div>
+`, strings.Join(items, "\n"))
+}
@@ -3,8 +3,10 @@ package html
 import (
 	"fmt"
 	"html"
+	"html/template"
 	"strings"
 
+	"github.com/thecsw/darkness/emilia/kaori"
 	"github.com/thecsw/darkness/emilia/narumi"
 	"github.com/thecsw/darkness/yunyun"
 	"github.com/thecsw/gana"
@@ -54,18 +56,29 @@ func (e *state) resolveDivTags(built string) string {
 
 // heading gives us a heading html representation.
 func (e *state) heading(content *yunyun.Content) string {
+	id := html.EscapeString(e.headingId(content))
 	toReturn := fmt.Sprintf(`
-<h%d id="%s" class="section-%d">%s</h%d>`,
+<h%d id="%s" class="section-%d">%s<a class="permalink" href="#%s" title="Permalink to this heading">¶</a></h%d>`,
 		content.HeadingLevelAdjusted, // HTML open tag
-		ExtractID(content.Heading),   // ID
+		id,                           // ID
 		content.HeadingLevel,         // section class
-		processText(content.Heading), // Actual title
+		e.text(content.Heading),      // Actual title
+		id,                           // Permalink
 		content.HeadingLevelAdjusted, // HTML close tag
 	)
 	e.inHeading = true
 	return toReturn
 }
 
+// headingId returns the heading's unique id on the page, headings added
+// after preparing, like the table of contents, get theirs from the text.
+func (e *state) headingId(content *yunyun.Content) string {
+	if id, ok := e.headingIds[content]; ok {
+		return id
+	}
+	return ExtractID(content.Heading)
+}
+
 func paragraphClass(content *yunyun.Content) string {
 	if content.IsQuote() {
 		return "quote"
@@ -89,18 +102,18 @@ func (e *state) paragraph(content *yunyun.Content) string {
 </p>
 </div>`,
 		// div class
-		paragraphClass(content), content.CustomHtmlTags, processText(content.Paragraph),
+		paragraphClass(content), content.CustomHtmlTags, e.text(content.Paragraph),
 	)
 }
 
 // makeListItem makes an html item
-func makeListItem(item yunyun.ListItem) string {
+func (e *state) makeListItem(item yunyun.ListItem) string {
 	return fmt.Sprintf(`
 <li class="l%d">
 <p>
 %s
 </p>
-</li>`, item.Level, processText(item.Text))
+</li>`, item.Level, e.text(item.Text))
 }
 
 // list gives us a list html representation
@@ -117,34 +130,47 @@ func (e *state) list(content *yunyun.Content) string {
 </div>
 `,
 		content.Summary, // overloaded summary to store list class
-		strings.Join(gana.Map(makeListItem, content.List), "\n"))
+		strings.Join(gana.Map(e.makeListItem, content.List), "\n"))
 }
 
 // listNumbered gives us a numbered list html representation
 func (e *state) listNumbered(content *yunyun.Content) string {
-	// TODO
-	return ""
+	return fmt.Sprintf(`
+<div class="olist">
+<ol class="%s">
+%s
+</ol>
+</div>
+`,
+		content.Summary, // overloaded summary to store list class
+		strings.Join(gana.Map(e.makeListItem, content.List), "\n"))
 }
 
 // sourceCode gives us a source code html representation
 func (e *state) sourceCode(content *yunyun.Content) string {
+	lang := narumi.MapSourceCodeLang(content.SourceCodeLang)
+	// Remove the nested parser blockers
+	code := strings.ReplaceAll(content.SourceCode, ",#", "#")
+	class := "language-" + lang
+	if e.conf.Website.SyntaxHighlightingStatic {
+		// Color the code right away, which escapes it too.
+		code, _ = kaori.Highlight(lang, code)
+		class += " hljs"
+	} else {
+		// Escape the whatever HTML that is found in source code
+		code = html.EscapeString(code)
+	}
 	return fmt.Sprintf(`
 <div class="coding" %s>
 <div class="listingblock">
-<pre class="highlight"><code class="language-%s" data-lang="%s">%s</code></pre>
+<pre class="highlight"><code class="%s" data-lang="%s">%s</code></pre>
 </div>
 </div>
 `,
 		content.CustomHtmlTags,
-		narumi.MapSourceCodeLang(content.SourceCodeLang),
+		class,
 		content.SourceCodeLang,
-		func() string {
-			// Remove the nested parser blockers
-			s := strings.ReplaceAll(content.SourceCode, ",#", "#")
-			// Escape the whatever HTML that is found in source code
-			s = html.EscapeString(s)
-			return s
-		}(),
+		code,
 	)
 }
 
@@ -154,11 +180,14 @@ func (e *state) rawHtml(content *yunyun.Content) string {
 	if content.IsRawHtmlUnsafe() {
 		return content.RawHtml
 	}
+	embed := e.newEmbed(content, "")
+	embed.Html = template.HTML(content.RawHtml)
 	// If responsive enabled, wrap the inner iframe (*probably*) in it.
 	if content.IsRawHtmlResponsive() {
-		return fmt.Sprintf(responsiveIFrameHtmlTemplate, content.CustomHtmlTags, content.RawHtml)
+		return e.render("responsive-html", embed)
 	}
-	return fmt.Sprintf(rawHtmlTemplate, content.CustomHtmlTags, content.RawHtml, content.Caption)
+	embed.Caption = template.HTML(content.Caption)
+	return e.render("raw-html", embed)
 }
 
 // horizontalLine gives us a horizontal line html representation
@@ -182,7 +211,7 @@ func (e *state) attentionBlock(content *yunyun.Content) string {
 </td>
 </tr>
 </table>
-</div>`, content.AttentionTitle, processText(content.AttentionText))
+</div>`, content.AttentionTitle, e.text(content.AttentionText))
 }
 
 // table gives an HTML formatted table
@@ -195,7 +224,7 @@ func (e *state) table(content *yunyun.Content) string {
 		headers = make([]string, len(content.Table[0]))
 		numRows--
 		for j, header := range content.Table[0] {
-			headers[j] = fmt.Sprintf("<th>%s</th>", processTableCell(header))
+			headers[j] = fmt.Sprintf("<th>%s</th>", e.processTableCell(header))
 		}
 	}
 
@@ -209,7 +238,7 @@ func (e *state) table(content *yunyun.Content) string {
 		// Make the rows.
 		for i, row := range content.Table {
 			for j, v := range row {
-				content.Table[i][j] = fmt.Sprintf("<td>%s</td>", processTableCell(v))
+				content.Table[i][j] = fmt.Sprintf("<td>%s</td>", e.processTableCell(v))
 			}
 			rows[i] = fmt.Sprintf("<tr>\n%s</tr>", strings.Join(content.Table[i], "\n"))
 		}
@@ -230,15 +259,18 @@ func (e *state) table(content *yunyun.Content) string {
 		strings.Join(headers, "\n"),
 		strings.Join(rows, "\n"),
 	)
-	return fmt.Sprintf(tableTemplate, content.CustomHtmlTags, content.Caption, tableHtml)
+	embed := e.newEmbed(content, "")
+	embed.Caption = template.HTML(content.Caption)
+	embed.Html = template.HTML(tableHtml)
+	return e.render("table", embed)
 }
 
 // processTableCell returns the HTML representation of a table cell given its content.
-func processTableCell(what string) string {
+func (e *state) processTableCell(what string) string {
 	if insideCell, isSpecial := tableSpecialCell(what); isSpecial {
 		return insideCell
 	}
-	return processText(what)
+	return e.text(what)
 }
 
 const (
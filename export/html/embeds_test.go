@@ -0,0 +1,40 @@
+package html
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestLinkEmbeds(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	initMarkupHtmlMapping()
+	conf := &alpha.DarknessConfig{}
+	conf.Runtime.WorkDir = alpha.WorkingDirectory(t.TempDir())
+	conf.Website.ClickableImages = true
+	e := &state{conf: conf, page: &yunyun.Page{Accoutrement: &yunyun.Accoutrement{}}, theme: loadTheme(conf)}
+	tests := []struct {
+		name     string
+		link     string
+		contains []string
+	}{
+		{"Relative audio", "file:../../audio/ep.mp3", []string{`<source src="file:../../audio/ep.mp3" type="audio/mpeg">`}},
+		{"Relative image", "file:images/cat.png", []string{`href="file:images/cat.png"`, `src="file:images/cat.png"`}},
+		{"Relative video", "clips/cat.mp4", []string{`<source src="clips/cat.mp4" type="video/mp4">`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := e.link(&yunyun.Content{Type: yunyun.TypeLink, Link: tt.link, LinkTitle: "Episode"})
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("link() = %v, want it to contain %v", got, want)
+				}
+			}
+			if strings.Contains(got, "ZgotmplZ") {
+				t.Errorf("link() = %v, has a filtered link", got)
+			}
+		})
+	}
+}
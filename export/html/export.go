@@ -3,6 +3,7 @@ package html
 import (
 	_ "embed"
 	"fmt"
+	"html/template"
 	"io"
 	"path/filepath"
 	"regexp"
@@ -10,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/thecsw/darkness/emilia/alpha/roxy"
+	"github.com/thecsw/darkness/emilia/narumi"
 	"github.com/thecsw/darkness/emilia/puck"
 	"github.com/thecsw/darkness/ichika/akane"
 	"github.com/thecsw/darkness/yunyun"
@@ -27,8 +29,22 @@ var (
 	darknessBanner = "<!--\n" + darknessBannerSource + "\n-->\n"
 )
 
+// Do exports the page into a complete html document.
 func (e ExporterHtml) Do(page *yunyun.Page) io.Reader {
-	s := &state{conf: e.Config, page: page}
+	return e.newState(page).export()
+}
+
+// Body exports only the page's contents and footnotes, without the
+// html head and the author header, like for feed readers.
+func (e ExporterHtml) Body(page *yunyun.Page) string {
+	s := e.newState(page)
+	s.prepare()
+	return s.body()
+}
+
+// newState returns a new exporting state of the page.
+func (e ExporterHtml) newState(page *yunyun.Page) *state {
+	s := &state{conf: e.Config, page: page, theme: loadTheme(e.Config)}
 	s.contentFunctions = []func(*yunyun.Content) string{
 		s.heading,
 		s.paragraph,
@@ -42,25 +58,41 @@ func (e ExporterHtml) Do(page *yunyun.Page) io.Reader {
 		s.table,
 		s.details,
 	}
-	return s.export()
+	return s
 }
 
 // Export runs the process of exporting
 func (e *state) export() io.Reader {
-	// Initialize the html mapping after yunyun built regexes.
-	markupHtmlMappingSetOnce.Do(func() {
-		markupHtmlMapping = map[*regexp.Regexp]string{
-			yunyun.BoldItalicText:    `$l<strong><em>$text</em></strong>$r`,
-			yunyun.ItalicBoldText:    `$l<em><strong>$text</strong></em>$r`,
-			yunyun.ItalicText:        `$l<em>$text</em>$r`,
-			yunyun.BoldText:          `$l<strong>$text</strong>$r`,
-			yunyun.VerbatimText:      `$l<code>$text</code>$r`,
-			yunyun.StrikethroughText: `$l<s>$text</s>$r`,
-			yunyun.UnderlineText:     `$l<u>$text</u>$r`,
-			yunyun.SuperscriptText:   `$l<sup>$text</sup>$r`,
-			yunyun.SubscriptText:     `$l<sub>$text</sub>$r`,
-		}
-	})
+	e.prepare()
+
+	if e.page.Accoutrement.Toc.IsEnabled() {
+		e.page.Contents = append(e.toc(), e.page.Contents...)
+	}
+
+	if e.page.Accoutrement.PreviewGenerate.IsEnabled() {
+		e.page.Accoutrement.PreviewWidth = puck.PagePreviewWidthString
+		e.page.Accoutrement.PreviewHeight = puck.PagePreviewHeightString
+		akane.RequestPagePreview(e.page.Location, e.page.Title, e.page.Date)
+	}
+
+	return strings.NewReader(e.render("page.html", themePage{
+		Page:       e.page,
+		Config:     e.conf,
+		Banner:     template.HTML(darknessBanner),
+		Head:       template.HTML(e.combineAndFilterHtmlHead()),
+		Title:      template.HTML(processTitle(flattenFormatting(e.page.Title))),
+		Header:     template.HTML(e.authorHeader()),
+		Tags:       template.HTML(e.tagBadges()),
+		Contents:   gana.Map(func(s string) template.HTML { return template.HTML(s) }, e.contents()),
+		Navigation: template.HTML(e.navigation()),
+		Footnotes:  template.HTML(e.addFootnotes()),
+		Backlinks:  template.HTML(e.backlinks()),
+	}))
+}
+
+// prepare sets up the exporting of the page's contents.
+func (e *state) prepare() {
+	initMarkupHtmlMapping()
 
 	// Add the red tomb to the last paragraph on given directories.
 	// Only trigger if the tombs were manually flipped.
@@ -71,46 +103,23 @@ func (e *state) export() io.Reader {
 	if len(e.page.Accoutrement.Preview) < 1 {
 		e.page.Accoutrement.Preview = string(e.conf.Website.Preview)
 	}
+	e.headingIds = HeadingIDs(e.page.Contents.Headings())
+}
 
-	if e.page.Accoutrement.Toc.IsEnabled() {
-		e.page.Contents = append(e.toc(), e.page.Contents...)
-	}
-
-	if e.page.Accoutrement.PreviewGenerate.IsEnabled() {
-		e.page.Accoutrement.PreviewWidth = puck.PagePreviewWidthString
-		e.page.Accoutrement.PreviewHeight = puck.PagePreviewHeightString
-		akane.RequestPagePreview(e.page.Location, e.page.Title, e.page.Date)
-	}
+// body returns the HTML representation of the contents and footnotes.
+func (e *state) body() string {
+	return strings.Join(e.contents(), "") + "\n" + e.navigation() + e.addFootnotes()
+}
 
-	// Build the HTML (string) representation of each content
+// contents returns the HTML representation of each content.
+func (e *state) contents() []string {
 	content := make([]string, 0, len(e.page.Contents))
 	for i, v := range e.page.Contents {
 		e.currentContentIndex = i
 		e.currentContent = v
 		content = append(content, e.buildContent(v))
 	}
-
-	output := fmt.Sprintf(`%s<!DOCTYPE html>
-<html lang="en">
-<head>
-%s
-<title>%s</title>
-</head>
-<body class="article">
-%s
-%s
-%s
-</body>
-</html>`,
-		darknessBanner,
-		e.combineAndFilterHtmlHead(),
-		processTitle(flattenFormatting(e.page.Title)),
-		e.authorHeader(),
-		strings.Join(content, ""),
-		e.addFootnotes(),
-	)
-
-	return strings.NewReader(output)
+	return content
 }
 
 // buildContent builds the HTML representation of a content.
@@ -143,6 +152,11 @@ func (e *state) combineAndFilterHtmlHead() string {
 	return finalHead + "\n" + strings.Join(e.page.HtmlHead, "\n")
 }
 
+// permalinkStyle only shows the headings' permalinks on hover, which the
+// website's styles can change.
+const permalinkStyle = `<style>.permalink{margin-left:.3em;text-decoration:none;visibility:hidden}` +
+	`:is(h1,h2,h3,h4,h5,h6):hover>.permalink,.permalink:focus{visibility:visible}</style>` + "\n"
+
 // styleTags is the processed style tags.
 func (e *state) styleTags() []string {
 	content := make([]string, len(e.conf.Website.Styles)+len(e.page.Stylesheets))
@@ -155,55 +169,29 @@ func (e *state) styleTags() []string {
 			`<link rel="stylesheet" type="text/css" href="%s">`+"\n", stylePath,
 		)
 	}
-	return append(content, e.page.Stylesheets...)
+	return append(append([]string{permalinkStyle}, content...), e.page.Stylesheets...)
 }
 
 // defaultScripts are the default scripts.
 var defaultScripts = []string{
 	`<script type="module">document.documentElement.classList.remove("no-js");document.documentElement.classList.add("js");</script>`,
-	`<script async src="https://sandyuraz.com/scripts/time.js"></script>`,
 }
 
 // scriptTags returns the script tags.
 func (e *state) scriptTags() []string {
-	return append(defaultScripts, e.page.Scripts...)
-}
-
-func (e *state) rssLink() string {
-	if !e.conf.RSS.Enable {
-		return ""
-	}
-	return `<span><a href="/feed.xml" class="rss-link"><img src="/assets/rss.svg" class="rss-icon"></a></span><br>` + "\n"
-}
-
-func (e *state) authorName() string {
-	if !e.conf.Author.NameEnable {
-		return ""
-	}
-	return `<span id="author" class="author">` + e.conf.Author.Name + `</span><br>` + "\n"
-}
-
-func (e *state) authorEmail() string {
-	if !e.conf.Author.EmailEnable {
-		return ""
+	scripts := append([]string{}, defaultScripts...)
+	scripts = append(scripts, fmt.Sprintf(`<script async src="%s"></script>`, narumi.TimeScript.Url(e.conf)))
+	if e.conf.Website.Search {
+		scripts = append(scripts, fmt.Sprintf(`<script defer src="%s" data-index="%s"></script>`,
+			e.conf.Runtime.Join(puck.SearchScriptFile), e.conf.Runtime.Join(puck.SearchIndexFile)))
 	}
-	return `<span id="email" class="email">` + e.conf.Author.Email + `</span><br>` + "\n"
+	return append(scripts, e.page.Scripts...)
 }
 
 // authorHeader returns the author header.
 func (e *state) authorHeader() string {
-	content := fmt.Sprintf(`
-<div class="header">
-<h1 class="section-1">%s%s</h1>
-<div class="menu">
-%s%s%s`,
-		e.authorImage(), processTitle(e.page.Title),
-		e.rssLink(), e.authorName(), e.authorEmail(),
-	)
-	content += `<span id="revdate">` + "\n"
-
 	// Build the navigation links.
-	navLinks := make([]string, 0, len(e.conf.Navigation))
+	links := make([]themeLink, 0, len(e.conf.Navigation))
 
 	// Go through elements.
 	for i := 1; i <= len(e.conf.Navigation); i++ {
@@ -230,42 +218,38 @@ func (e *state) authorHeader() string {
 		}
 
 		// Otherwise, join against the relative path of this page.
-		navLinks = append(navLinks,
-			fmt.Sprintf(`<a href="%s">%s</a>`,
-				e.conf.Runtime.Join(yunyun.RelativePathFile(whatToJoin)),
-				v.Title,
-			))
-
+		links = append(links, themeLink{
+			Url:   template.URL(e.conf.Runtime.Join(yunyun.RelativePathFile(whatToJoin))),
+			Title: template.HTML(v.Title),
+		})
 	}
 
-	// Close the navigation links span.
-	content += strings.Join(navLinks, " | ") + `</span>`
-
-	// Add the Holoscene time element.
-	content += `
-</div>
-<div id="hetime" class="menu"></div>
-</div>`
-
-	plugins := roxy.FormatForHTMLExport(e.conf.Runtime.PluginConfigs, roxy.AuthorHeader)
-	for _, p := range plugins {
+	plugins := ""
+	for _, p := range roxy.FormatForHTMLExport(e.conf.Runtime.PluginConfigs, roxy.AuthorHeader) {
 		funcMap := p.Do.(map[string]roxy.HTMLExportDo)
 		if do, ok := funcMap[roxy.AuthorHeader]; ok {
-			content += do(p.Data, e.conf)
+			plugins += do(p.Data, e.conf)
 		}
-
 	}
+
 	// Return the website header.
-	return content
+	return e.render("header.html", themeHeader{
+		Page:    e.page,
+		Config:  e.conf,
+		Title:   template.HTML(e.title(e.page.Title)),
+		Image:   template.URL(e.authorImage()),
+		Links:   links,
+		Plugins: template.HTML(plugins),
+	})
 }
 
-// authorHeader returns img element if author header image is given.
+// authorImage returns the author's image if it's given.
 func (e *state) authorImage() string {
 	// Return nothing if it's not provided.
 	if e.conf.Author.Image == "" || e.page.Accoutrement.AuthorImage.IsDisabled() {
 		return ""
 	}
-	return fmt.Sprintf(`<img id="myface" src="%s" alt="avatar">`, e.conf.Author.ImagePreComputed)
+	return string(e.conf.Author.ImagePreComputed)
 }
 
 // addTomb adds the tomb to the last paragraph.
@@ -310,3 +294,20 @@ func (e *state) toc() []*yunyun.Content {
 		},
 	}
 }
+
+// initMarkupHtmlMapping initializes the html mapping after yunyun built regexes.
+func initMarkupHtmlMapping() {
+	markupHtmlMappingSetOnce.Do(func() {
+		markupHtmlMapping = map[*regexp.Regexp]string{
+			yunyun.BoldItalicText:    `$l<strong><em>$text</em></strong>$r`,
+			yunyun.ItalicBoldText:    `$l<em><strong>$text</strong></em>$r`,
+			yunyun.ItalicText:        `$l<em>$text</em>$r`,
+			yunyun.BoldText:          `$l<strong>$text</strong>$r`,
+			yunyun.VerbatimText:      `$l<code>$text</code>$r`,
+			yunyun.StrikethroughText: `$l<s>$text</s>$r`,
+			yunyun.UnderlineText:     `$l<u>$text</u>$r`,
+			yunyun.SuperscriptText:   `$l<sup>$text</sup>$r`,
+			yunyun.SubscriptText:     `$l<sub>$text</sub>$r`,
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package html
 
 import (
+	"html/template"
+
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/yunyun"
 )
@@ -29,4 +31,8 @@ type state struct {
 	inWriting bool
 	// conf is the configuration for the exporter.
 	conf *alpha.DarknessConfig
+	// theme is the templates the page is rendered with.
+	theme *template.Template
+	// headingIds are the unique ids of the page's headings.
+	headingIds map[*yunyun.Content]string
 }
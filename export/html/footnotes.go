@@ -1,8 +1,7 @@
 package html
 
 import (
-	"fmt"
-	"strings"
+	"html/template"
 
 	"github.com/thecsw/darkness/emilia/narumi"
 )
@@ -12,20 +11,17 @@ func (e *state) addFootnotes() string {
 	if len(e.page.Footnotes) < 1 {
 		return ""
 	}
-	footnotes := make([]string, len(e.page.Footnotes))
+	footnotes := make([]themeFootnote, len(e.page.Footnotes))
 	for i, footnote := range e.page.Footnotes {
-		footnotes[i] = fmt.Sprintf(`
-<div class="footnote" id="_footnotedef_%d">
-<a href="#_footnoteref_%d">%s</a>
-%s
-</div>
-`,
-			i+1, i+1, narumi.FootnoteLabeler(i+1), processText(footnote))
+		footnotes[i] = themeFootnote{
+			Number: i + 1,
+			Label:  narumi.FootnoteLabeler(i + 1),
+			Text:   template.HTML(e.text(footnote)),
+		}
 	}
-	return fmt.Sprintf(`
-<div id="footnotes">
-<hr>
-%s
-</div>
-`, strings.Join(footnotes, ""))
+	return e.render("footnotes.html", themeFootnotes{
+		Page:      e.page,
+		Config:    e.conf,
+		Footnotes: footnotes,
+	})
 }
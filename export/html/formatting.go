@@ -8,10 +8,22 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/thecsw/darkness/emilia/kurisu"
 	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/emilia/puck"
 	"github.com/thecsw/darkness/yunyun"
 )
 
+const (
+	// mathStart and mathEnd surround the placeholders of the math that is
+	// already MathML, which the formatting shouldn't touch.
+	mathStart = "\uE010"
+	mathEnd   = "\uE011"
+)
+
+// codeRegexp matches the verbatim text after it's been marked up.
+var codeRegexp = regexp.MustCompile(`(?s)<code>.*?</code>`)
+
 // markupHtmlMapping maps the regex markup to html replacements
 var (
 	markupHtmlMapping        map[*regexp.Regexp]string
@@ -36,7 +48,7 @@ func processText(text string) string {
 	text = strings.ReplaceAll(text, "◼", `<b style="color:var(--color-tomb)">◼︎</b>`)
 	text = yunyun.LinkRegexp.ReplaceAllString(text,
 		fmt.Sprintf(`<a href="%s" title="%s">%s</a>`, `$link`, `$desc`, `$text`))
-	text = yunyun.MathRegexp.ReplaceAllString(text, `\($1\)`)
+	text = mathOutsideCode(text)
 	text = yunyun.FootnotePostProcessingRegexp.ReplaceAllStringFunc(text, func(what string) string {
 		num, _ := strconv.Atoi(strings.ReplaceAll(what, "!", ""))
 		// get the footnote HTML body
@@ -52,7 +64,64 @@ func processText(text string) string {
 
 // processTitle returns a properly formatted HTML of a title
 func processTitle(title string) string {
-	return yunyun.MathRegexp.ReplaceAllString(markupHtml(yunyun.FancyText(title)), `\($1\)`)
+	return mathOutsideCode(markupHtml(yunyun.FancyText(title)))
+}
+
+// mathOutsideCode marks the math for KaTeX, where the code is left as it is.
+func mathOutsideCode(text string) string {
+	result := strings.Builder{}
+	last := 0
+	for _, code := range codeRegexp.FindAllStringIndex(text, -1) {
+		result.WriteString(yunyun.MathRegexp.ReplaceAllString(text[last:code[0]], `\($1\)`))
+		result.WriteString(text[code[0]:code[1]])
+		last = code[1]
+	}
+	result.WriteString(yunyun.MathRegexp.ReplaceAllString(text[last:], `\($1\)`))
+	return result.String()
+}
+
+// text returns a properly formatted HTML of a text with its math as MathML.
+func (e *state) text(text string) string {
+	return e.withMath(text, processText)
+}
+
+// title returns a properly formatted HTML of a title with its math as MathML.
+func (e *state) title(title string) string {
+	return e.withMath(title, processTitle)
+}
+
+// withMath processes the text with its math replaced by placeholders,
+// which get the MathML of the math after. The math that can't be
+// converted stays for KaTeX, see `narumi.WithMathSupport`.
+func (e *state) withMath(text string, process func(string) string) string {
+	if e.page.Accoutrement.Math.IsDisabled() {
+		return process(text)
+	}
+	// Math in code or in links' targets is left as it is.
+	found := kurisu.FindAllOutside(text, yunyun.MathlessRanges(text))
+	if len(found) < 1 {
+		return process(text)
+	}
+	rendered := make([]string, 0, len(found))
+	replaced := strings.Builder{}
+	last := 0
+	for _, math := range found {
+		mathml, err := math.MathML()
+		if err != nil {
+			puck.Logger.Debug("Leaving math to katex", "page", e.page.File, "err", err)
+			continue
+		}
+		replaced.WriteString(text[last:math.Start])
+		replaced.WriteString(mathStart + strconv.Itoa(len(rendered)) + mathEnd)
+		rendered = append(rendered, mathml)
+		last = math.End
+	}
+	replaced.WriteString(text[last:])
+	processed := process(replaced.String())
+	for i, mathml := range rendered {
+		processed = strings.Replace(processed, mathStart+strconv.Itoa(i)+mathEnd, mathml, 1)
+	}
+	return processed
 }
 
 // flattenFormatting returns a plain-text to be fit into the description
@@ -0,0 +1,40 @@
+package html
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestTextMath(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	e := &state{page: &yunyun.Page{Accoutrement: &yunyun.Accoutrement{}}}
+	initMarkupHtmlMapping()
+	tests := []struct {
+		name     string
+		text     string
+		mathml   int
+		contains string
+	}{
+		{"Inline math", `so $x$ is`, 1, ``},
+		{"Math in the link's target", `[[https://x.org/?a=$b$][a $dollar$ link]]`, 1, `href="https://x.org/?a=`},
+		{"Math in verbatim", `=echo $HOME and $PATH=`, 0, `<code>echo $HOME and $PATH</code>`},
+		{"Math next to verbatim", `=cost $5= and $x$`, 1, `<code>cost $5</code>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := e.text(tt.text)
+			if count := strings.Count(got, "<math "); count != tt.mathml {
+				t.Errorf("text() = %v, has %d math, want %d", got, count, tt.mathml)
+			}
+			if !strings.Contains(got, tt.contains) {
+				t.Errorf("text() = %v, want it to contain %v", got, tt.contains)
+			}
+			// The math should never end up in the attributes.
+			if strings.Contains(got, `="<math`) || strings.Contains(got, `=<math`) {
+				t.Errorf("text() = %v, has math in an attribute", got)
+			}
+		})
+	}
+}
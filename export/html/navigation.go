@@ -0,0 +1,56 @@
+package html
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// navigation returns the links to the previous and next pages, with the
+// page's part in the series if it's in one.
+func (e *state) navigation() string {
+	navigation := e.page.Navigation
+	if navigation == nil {
+		return ""
+	}
+	links := make([]string, 0, 3)
+	if len(navigation.Series) > 0 {
+		links = append(links, fmt.Sprintf(`<div class="series">Part %d of %d in <em>%s</em></div>`,
+			navigation.Part, navigation.Parts, html.EscapeString(navigation.Series)))
+	}
+	if navigation.Previous != nil {
+		links = append(links, fmt.Sprintf(`<a class="previous" rel="prev" href="%s">← %s</a>`,
+			e.conf.Runtime.Join(yunyun.RelativePathFile(navigation.Previous.Location)),
+			e.title(navigation.Previous.Title),
+		))
+	}
+	if navigation.Next != nil {
+		links = append(links, fmt.Sprintf(`<a class="next" rel="next" href="%s">%s →</a>`,
+			e.conf.Runtime.Join(yunyun.RelativePathFile(navigation.Next.Location)),
+			e.title(navigation.Next.Title),
+		))
+	}
+	return fmt.Sprintf(`
+<nav class="navigation">
+%s
+</nav>
+`, strings.Join(links, "\n"))
+}
+
+// navigationLinks returns the previous and next pages as link tags.
+func (e *state) navigationLinks() []rel {
+	navigation := e.page.Navigation
+	if navigation == nil {
+		return nil
+	}
+	links := make([]rel, 0, 2)
+	if navigation.Previous != nil {
+		links = append(links, rel{"prev", e.conf.Runtime.Join(yunyun.RelativePathFile(navigation.Previous.Location)), ""})
+	}
+	if navigation.Next != nil {
+		links = append(links, rel{"next", e.conf.Runtime.Join(yunyun.RelativePathFile(navigation.Next.Location)), ""})
+	}
+	return links
+}
@@ -0,0 +1,29 @@
+package html
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// tagBadges returns the page's tags as badges, which link to the
+// tag pages if the website builds them.
+func (e *state) tagBadges() string {
+	if len(e.page.Tags) < 1 {
+		return ""
+	}
+	badges := make([]string, 0, len(e.page.Tags))
+	for _, tag := range e.page.Tags {
+		if len(e.conf.Website.Tags) < 1 {
+			badges = append(badges, fmt.Sprintf(`<span class="tag">%s</span>`, html.EscapeString(tag)))
+			continue
+		}
+		badges = append(badges, fmt.Sprintf(`<a class="tag" href="%s">%s</a>`,
+			e.conf.Runtime.Join(yunyun.JoinRelativePaths(e.conf.Website.Tags, yunyun.RelativePathFile(yunyun.TagSlug(tag)))),
+			html.EscapeString(tag),
+		))
+	}
+	return "\n" + `<div class="tags">` + "\n" + strings.Join(badges, "\n") + "\n</div>"
+}
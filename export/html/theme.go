@@ -0,0 +1,175 @@
+package html
+
+import (
+	"embed"
+	"html/template"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+var (
+	//go:embed theme/*.html
+	defaultTheme embed.FS
+
+	// themes are the loaded themes by their configs.
+	themes = map[*alpha.DarknessConfig]*template.Template{}
+	// themesLock guards the themes, as pages are exported concurrently.
+	themesLock sync.Mutex
+)
+
+// themePage is what the page template, `page.html`, is given.
+type themePage struct {
+	// Page is the page being exported.
+	Page *yunyun.Page
+	// Config is the website's config.
+	Config *alpha.DarknessConfig
+	// Banner is the darkness banner comment.
+	Banner template.HTML
+	// Head is everything that goes into the html head.
+	Head template.HTML
+	// Title is the page's title as text.
+	Title template.HTML
+	// Header is the rendered `header.html`.
+	Header template.HTML
+	// Tags are the page's tag badges.
+	Tags template.HTML
+	// Contents are the page's rendered contents, one by one.
+	Contents []template.HTML
+	// Navigation is the previous and next pages.
+	Navigation template.HTML
+	// Footnotes is the rendered `footnotes.html`.
+	Footnotes template.HTML
+	// Backlinks is the "Linked from" section.
+	Backlinks template.HTML
+}
+
+// themeHeader is what the author header template, `header.html`, is given.
+type themeHeader struct {
+	// Page is the page being exported.
+	Page *yunyun.Page
+	// Config is the website's config.
+	Config *alpha.DarknessConfig
+	// Title is the page's rendered title.
+	Title template.HTML
+	// Image is the author's image, empty if it's not shown.
+	Image template.URL
+	// Links are the navigation links of the website.
+	Links []themeLink
+	// Plugins is whatever the plugins add to the header.
+	Plugins template.HTML
+}
+
+// themeLink is a navigation link in the header.
+type themeLink struct {
+	// Url is the full url of the link.
+	Url template.URL
+	// Title is the link's title.
+	Title template.HTML
+}
+
+// themeFootnotes is what the footnotes template, `footnotes.html`, is given.
+type themeFootnotes struct {
+	// Page is the page being exported.
+	Page *yunyun.Page
+	// Config is the website's config.
+	Config *alpha.DarknessConfig
+	// Footnotes are the page's footnotes in order.
+	Footnotes []themeFootnote
+}
+
+// themeFootnote is a single footnote.
+type themeFootnote struct {
+	// Number is the footnote's number, starting from one.
+	Number int
+	// Label is how the footnote's number is shown.
+	Label string
+	// Text is the footnote's rendered text.
+	Text template.HTML
+}
+
+// themeEmbed is what the embed templates in `embeds.html` are given.
+type themeEmbed struct {
+	// Page is the page being exported.
+	Page *yunyun.Page
+	// Config is the website's config.
+	Config *alpha.DarknessConfig
+	// Attributes are the content's custom html attributes.
+	Attributes template.HTMLAttr
+	// Link is the embedded link, which is used as it is given.
+	Link template.URL
+	// Id is the id of the youtube video or the spotify item.
+	Id string
+	// Type is the video's file type.
+	Type string
+	// Title is the embed's rendered title.
+	Title template.HTML
+	// Description is the link's description as text.
+	Description string
+	// Alt is the image's alternative text.
+	Alt string
+	// Caption is the caption of the raw html or the table.
+	Caption template.HTML
+	// Html is the raw html or the table.
+	Html template.HTML
+	// Clickable tells whether the image links to itself.
+	Clickable bool
+	// Service is what the click-to-load placeholder loads, like "YouTube video".
+	Service string
+}
+
+// loadTheme returns the default theme, where the website's own templates
+// from the theme directory replace the ones with the same names.
+func loadTheme(conf *alpha.DarknessConfig) *template.Template {
+	themesLock.Lock()
+	defer themesLock.Unlock()
+	if theme, ok := themes[conf]; ok {
+		return theme
+	}
+	theme := template.Must(template.New("theme").
+		Funcs(themeFunctions(conf)).
+		ParseFS(defaultTheme, "theme/*.html"))
+	directory := conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(puck.ThemeDirectory))
+	overrides, err := filepath.Glob(filepath.Join(string(directory), "*.html"))
+	if err != nil {
+		puck.Logger.Fatal("Finding theme templates", "dir", directory, "err", err)
+	}
+	if len(overrides) > 0 {
+		if theme, err = theme.ParseFiles(overrides...); err != nil {
+			puck.Logger.Fatal("Parsing theme templates", "dir", directory, "err", err)
+		}
+	}
+	themes[conf] = theme
+	return theme
+}
+
+// ForgetTheme drops the config's loaded theme, so that the next export
+// loads the templates again with their changes.
+func ForgetTheme(conf *alpha.DarknessConfig) {
+	themesLock.Lock()
+	defer themesLock.Unlock()
+	delete(themes, conf)
+}
+
+// themeFunctions are the functions the templates can call.
+func themeFunctions(conf *alpha.DarknessConfig) template.FuncMap {
+	return template.FuncMap{
+		// url returns the full url of a path relative to the website.
+		"url": func(path string) string {
+			return string(conf.Runtime.Join(yunyun.RelativePathFile(path)))
+		},
+	}
+}
+
+// render executes the theme's template, which logs what went wrong.
+func (e *state) render(name string, data any) string {
+	var output strings.Builder
+	if err := e.theme.ExecuteTemplate(&output, name, data); err != nil {
+		puck.Logger.Error("Rendering theme template", "template", name, "page", e.page.File, "err", err)
+	}
+	return output.String()
+}
@@ -2,25 +2,60 @@ package html
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/thecsw/darkness/yunyun"
 )
 
+// defaultHeadingID is the id of the headings that have no letters or digits.
+const defaultHeadingID = "section"
+
 // GenerateTableOfContents generates a table of contents for a page.
 func GenerateTableOfContents(page *yunyun.Page) []yunyun.ListItem {
-	toc := make([]yunyun.ListItem, len(page.Contents.Headings()))
-	for i, heading := range page.Contents.Headings() {
+	headings := page.Contents.Headings()
+	ids := HeadingIDs(headings)
+	toc := make([]yunyun.ListItem, len(headings))
+	for i, heading := range headings {
 		toc[i] = yunyun.ListItem{
 			Level: uint8(heading.HeadingLevelAdjusted),
-			Text:  fmt.Sprintf("[[%s][%s]]", "#"+ExtractID(heading.Heading), heading.Heading),
+			Text:  fmt.Sprintf("[[%s][%s]]", "#"+ids[heading], heading.Heading),
 		}
 	}
 	return toc
 }
 
-// ExtractID returns a properly formatted ID for a heading title
+// HeadingIDs returns the ids of the headings, which are unique on the
+// page: the custom ids are kept as they are and the repeated headings
+// are numbered, like `notes`, `notes-1`, `notes-2`.
+func HeadingIDs(headings yunyun.Contents) map[*yunyun.Content]string {
+	ids := make(map[*yunyun.Content]string, len(headings))
+	taken := make(map[string]bool, len(headings))
+	// Custom ids go first, so that the others go around them.
+	for _, heading := range headings {
+		if yunyun.IsValidHeadingId(heading.HeadingId) {
+			ids[heading] = heading.HeadingId
+			taken[heading.HeadingId] = true
+		}
+	}
+	for _, heading := range headings {
+		if _, ok := ids[heading]; ok {
+			continue
+		}
+		id := ExtractID(heading.Heading)
+		unique := id
+		for i := 1; taken[unique]; i++ {
+			unique = id + "-" + strconv.Itoa(i)
+		}
+		ids[heading] = unique
+		taken[unique] = true
+	}
+	return ids
+}
+
+// ExtractID returns a properly formatted ID for a heading title, which
+// keeps the letters and digits of any language
 func ExtractID(heading string) string {
 	// Check if heading is a link
 	extractedLink := yunyun.ExtractLink(heading)
@@ -28,15 +63,18 @@ func ExtractID(heading string) string {
 		heading = extractedLink.Text // 0 is whole match, 1 is link, 2 is title
 	}
 
-	res := ""
+	res := strings.Builder{}
 	for _, c := range heading {
-		if unicode.IsSpace(c) || unicode.IsPunct(c) || unicode.IsSymbol(c) {
-			res += "-"
-			continue
-		}
-		if c <= unicode.MaxASCII {
-			res += string(unicode.ToLower(c))
+		switch {
+		case unicode.IsSpace(c) || unicode.IsPunct(c) || unicode.IsSymbol(c):
+			res.WriteByte('-')
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || unicode.IsMark(c):
+			res.WriteRune(unicode.ToLower(c))
 		}
 	}
-	return strings.TrimRight(res, "-")
+	id := strings.TrimRight(res.String(), "-")
+	if len(id) < 1 {
+		return defaultHeadingID
+	}
+	return id
 }
@@ -0,0 +1,48 @@
+package html
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestHeadingIDs(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	tests := []struct {
+		name     string
+		headings []string
+		custom   []string
+		want     []string
+	}{
+		{"Cyrillic", []string{"Привет, мир!"}, nil, []string{"привет--мир"}},
+		{"Japanese", []string{"日本語の見出し"}, nil, []string{"日本語の見出し"}},
+		{"No letters", []string{"🎉"}, nil, []string{"section"}},
+		{"Duplicates", []string{"Notes", "Notes", "Notes"}, nil, []string{"notes", "notes-1", "notes-2"}},
+		{"Custom ids", []string{"Notes"}, []string{"my-notes"}, []string{"my-notes"}},
+		{"Custom id takes the generated one", []string{"Notes", "Other"}, []string{"", "notes"},
+			[]string{"notes-1", "notes"}},
+		{"Custom id takes the numbered one", []string{"Notes", "Notes", "Other"}, []string{"", "", "notes-1"},
+			[]string{"notes", "notes-2", "notes-1"}},
+		{"Invalid custom ids are ignored", []string{"Notes"}, []string{`a "b"`}, []string{"notes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headings := make(yunyun.Contents, len(tt.headings))
+			for i, heading := range tt.headings {
+				headings[i] = &yunyun.Content{Type: yunyun.TypeHeading, Heading: heading}
+				if i < len(tt.custom) {
+					headings[i].HeadingId = tt.custom[i]
+				}
+			}
+			ids := HeadingIDs(headings)
+			got := make([]string, len(headings))
+			for i, heading := range headings {
+				got[i] = ids[heading]
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HeadingIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
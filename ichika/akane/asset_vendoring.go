@@ -0,0 +1,105 @@
+package akane
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/emilia/reze"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/rei"
+)
+
+// stylesheetUrlRegexp finds the urls in the stylesheets, like KaTeX's fonts.
+var stylesheetUrlRegexp = regexp.MustCompile(`url\(\s*["']?([^"')]+)["']?\s*\)`)
+
+// doAssetVendors downloads the remote assets that the offline websites
+// use, the ones that were already downloaded are left alone.
+func doAssetVendors(conf *alpha.DarknessConfig) {
+	for _, asset := range narumi.VendoredAssets() {
+		vendorAsset(conf, asset)
+	}
+}
+
+// vendorAsset downloads the asset into the vendor directory, together
+// with the files that it refers to if it's a stylesheet.
+func vendorAsset(conf *alpha.DarknessConfig, asset narumi.VendoredAsset) {
+	local := string(conf.Runtime.WorkDir.Join(
+		yunyun.JoinRelativePaths(conf.Project.DarknessVendorDirectory, asset.Local)))
+	isStylesheet := filepath.Ext(local) == ".css"
+
+	exists, err := rei.FileExists(local)
+	if err != nil {
+		logger.Error("Checking for vendored asset", "path", local, "err", err)
+		return
+	}
+	var data []byte
+	switch {
+	case exists && !isStylesheet:
+		return
+	case exists:
+		// Stylesheets are read again, in case their files failed before.
+		data, err = os.ReadFile(filepath.Clean(local))
+	default:
+		data, err = downloadAsset(asset, local)
+	}
+	if err != nil {
+		logger.Error("Vendoring asset", "url", asset.Remote, "err", err)
+		return
+	}
+	if !isStylesheet {
+		return
+	}
+	for _, match := range stylesheetUrlRegexp.FindAllStringSubmatch(string(data), -1) {
+		if referred, ok := referredAsset(asset, match[1]); ok {
+			vendorAsset(conf, referred)
+		}
+	}
+}
+
+// downloadAsset downloads the asset to the local path and returns its data.
+func downloadAsset(asset narumi.VendoredAsset, local string) ([]byte, error) {
+	data, err := reze.Download(asset.Remote, "vendor", "", string(asset.Local))
+	// Clear the progressbar.
+	fmt.Print("\r\033[2K")
+	if err != nil {
+		return nil, fmt.Errorf("downloading: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(local), 0o750); err != nil {
+		return nil, fmt.Errorf("creating directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Clean(local), data, 0o644); err != nil {
+		return nil, fmt.Errorf("writing: %v", err)
+	}
+	logger.Info("Vendored asset", "path", asset.Local)
+	return data, nil
+}
+
+// referredAsset returns the asset that the stylesheet's url refers to,
+// false if it's not a relative url next to the stylesheet.
+func referredAsset(stylesheet narumi.VendoredAsset, ref string) (narumi.VendoredAsset, bool) {
+	ref, _, _ = strings.Cut(ref, "#")
+	ref, _, _ = strings.Cut(ref, "?")
+	relative, err := url.Parse(ref)
+	if err != nil || relative.IsAbs() || strings.HasPrefix(ref, "/") || len(ref) < 1 {
+		return narumi.VendoredAsset{}, false
+	}
+	base, err := url.Parse(stylesheet.Remote)
+	if err != nil {
+		return narumi.VendoredAsset{}, false
+	}
+	local := path.Join(path.Dir(string(stylesheet.Local)), ref)
+	if strings.HasPrefix(local, "..") {
+		return narumi.VendoredAsset{}, false
+	}
+	return narumi.VendoredAsset{
+		Local:  yunyun.RelativePathFile(local),
+		Remote: base.ResolveReference(relative).String(),
+	}, true
+}
@@ -19,4 +19,10 @@ func Do(conf *alpha.DarknessConfig) {
 		logger.Info("Generating gallery vendors...", "gallery_vendors", len(galleryVendorsToDownload))
 		doGalleryVendors(conf)
 	}
+
+	if conf.Website.Offline {
+		// Do the remote assets vendoring.
+		logger.Info("Vendoring remote assets...")
+		doAssetVendors(conf)
+	}
 }
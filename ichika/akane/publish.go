@@ -0,0 +1,79 @@
+package akane
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/ichika/hizuru"
+)
+
+// PublishAssets hard-links (or copies, if it can't) the assets into the
+// output directory, so that the directory can be deployed as is.
+func PublishAssets(conf *alpha.DarknessConfig) {
+	if !conf.HasOutputDirectory() {
+		return
+	}
+	published := 0
+	for _, asset := range hizuru.FindAssets(conf) {
+		target := string(conf.Runtime.OutputDir.Join(conf.Runtime.WorkDir.Rel(asset)))
+		linked, err := publishAsset(string(asset), target)
+		if err != nil {
+			logger.Error("Publishing asset", "path", conf.Runtime.WorkDir.Rel(asset), "err", err)
+			continue
+		}
+		if linked {
+			published++
+		}
+	}
+	if published > 0 {
+		logger.Info("Published assets", "count", published, "dir", conf.Project.OutputDirectory)
+	}
+}
+
+// publishAsset links the source to the target, returns false if it was already there.
+func publishAsset(source, target string) (bool, error) {
+	sourceInfo, err := os.Stat(source)
+	if err != nil {
+		return false, fmt.Errorf("reading source: %v", err)
+	}
+	// Skip the hard links that are already in place, or copies that
+	// are not older than the source.
+	if targetInfo, err := os.Stat(target); err == nil {
+		if os.SameFile(sourceInfo, targetInfo) ||
+			(targetInfo.Size() == sourceInfo.Size() && !targetInfo.ModTime().Before(sourceInfo.ModTime())) {
+			return false, nil
+		}
+		if err := os.Remove(target); err != nil {
+			return false, fmt.Errorf("removing old target: %v", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return false, fmt.Errorf("creating target directory: %v", err)
+	}
+	// Hard links don't work across devices, copy the file then.
+	if err := os.Link(source, target); err == nil {
+		return true, nil
+	}
+	return true, copyFile(source, target)
+}
+
+// copyFile copies the source file's contents onto the target.
+func copyFile(source, target string) error {
+	sourceFile, err := os.Open(filepath.Clean(source))
+	if err != nil {
+		return fmt.Errorf("opening source: %v", err)
+	}
+	defer sourceFile.Close()
+	targetFile, err := os.Create(filepath.Clean(target))
+	if err != nil {
+		return fmt.Errorf("creating target: %v", err)
+	}
+	if _, err := io.Copy(targetFile, sourceFile); err != nil {
+		targetFile.Close()
+		return fmt.Errorf("copying: %v", err)
+	}
+	return targetFile.Close()
+}
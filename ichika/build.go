@@ -1,7 +1,9 @@
 package ichika
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"runtime"
 	"time"
 
@@ -9,9 +11,12 @@ import (
 	"github.com/thecsw/darkness/emilia/puck"
 	"github.com/thecsw/darkness/export"
 	"github.com/thecsw/darkness/ichika/akane"
+	"github.com/thecsw/darkness/ichika/frieren"
 	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/ichika/kaguya"
 	"github.com/thecsw/darkness/ichika/kuroko"
 	"github.com/thecsw/darkness/ichika/makima"
+	"github.com/thecsw/darkness/ichika/misa"
 	"github.com/thecsw/darkness/ichika/misaka"
 	"github.com/thecsw/darkness/parse"
 	"github.com/thecsw/darkness/yunyun"
@@ -23,15 +28,36 @@ import (
 func BuildCommandFunc() {
 	cmd := darknessFlagset(buildCommand)
 	conf := alpha.BuildConfig(getAlphaOptions(cmd))
-	build(conf)
+	if err := build(conf); err != nil {
+		puck.Logger.Error("Building", "err", err)
+		os.Exit(1)
+	}
 	fmt.Println("farewell")
 }
 
-// build uses set flags and emilia data to build the local directory.
-func build(conf *alpha.DarknessConfig) {
-	parser := parse.BuildParser(conf)
+// build uses set flags and emilia data to build the local directory,
+// returns an error if any of the pages failed.
+func build(conf *alpha.DarknessConfig) error {
+	parsers := parse.BuildParsers(conf)
 	exporter := export.BuildExporter(conf)
 
+	// Remember what has been built before, unless asked not to.
+	var cache *frieren.Cache
+	if !kuroko.Cacheless {
+		cache = frieren.Load(conf)
+	}
+	if cache != nil && kuroko.NoCache {
+		cache.Forget()
+	}
+
+	// Pages need to know who links to them and who is around them
+	// before they are exported.
+	graph := buildGraph(conf)
+	lastGraph = graph
+
+	// Assets are published last, after akane has generated hers.
+	defer akane.PublishAssets(conf)
+
 	if !kuroko.Akaneless {
 		// Let's complete the akane requests when done building.
 		defer akane.Do(conf)
@@ -46,11 +72,12 @@ func build(conf *alpha.DarknessConfig) {
 	go logErrors("reading", rei.Must(filesPool.Errors()))
 
 	// Create a pool that take a files handle and parses it out into yunyun pages.
-	parserPool := komi.NewWithSettings(komi.Work(makima.Woof.Parse), &komi.Settings{
+	parserPool := komi.NewWithSettings(komi.WorkWithErrors(makima.Woof.Parse), &komi.Settings{
 		Name:     "Komi Parsing 🧹 ",
 		Laborers: kuroko.CustomNumWorkers,
 		Debug:    kuroko.DebugEnabled,
 	})
+	go logErrors("parsing", rei.Must(parserPool.Errors()))
 
 	// Create a pool that that takes yunyun pages and exports them into request format.
 	exporterPool := komi.NewWithSettings(komi.Work(makima.Woof.Export), &komi.Settings{
@@ -98,8 +125,10 @@ func build(conf *alpha.DarknessConfig) {
 		// Submit the job to the pool.
 		rei.Try(filesPool.Submit(&makima.Control{
 			Conf:          conf,
-			Parser:        parser,
+			Parser:        parsers.For(inputFilename),
 			Exporter:      exporter,
+			Cache:         cache,
+			Graph:         graph,
 			InputFilename: inputFilename,
 		}))
 	}
@@ -113,19 +142,153 @@ func build(conf *alpha.DarknessConfig) {
 	// Clear the download progress bar if present by wiping out the line.
 	fmt.Print("\r\033[2K")
 
-	fmt.Printf("Processed %d files in %d ms\n", exporterPool.JobsSucceeded(), finish.Sub(start).Milliseconds())
+	if cache == nil {
+		fmt.Printf("Processed %d files in %d ms\n", exporterPool.JobsSucceeded(), finish.Sub(start).Milliseconds())
+	} else {
+		fmt.Printf("Processed %d files (%d unchanged) in %d ms\n",
+			exporterPool.JobsSucceeded(), cache.Skipped(), finish.Sub(start).Milliseconds())
+	}
 
 	// Let's process the misaka report if user wants to see it.
 	if kuroko.BuildReport {
 		misaka.WriteReport(conf)
 	}
+
+	// Let's generate the sitemap and other website pages now that the
+	// pages are built, where the pages' problems recorded by parsing
+	// them again are dropped, as the build has recorded them already.
+	diagnostics := misaka.TakeDiagnostics()
+	afterErr := misa.AfterBuild(conf)
+	misaka.TakeDiagnostics()
+	misaka.RecordDiagnostics(diagnostics...)
+
+	// Remember the builds for the next time, failed pages are never
+	// stored, so they will be built again anyway.
+	if cache != nil {
+		if err := cache.Save(); err != nil {
+			puck.Logger.Error("Saving the build cache", "err", err)
+		}
+	}
+
+	err := reportDiagnostics()
+	if afterErr != nil {
+		return errors.Join(fmt.Errorf("generating after the build: %v", afterErr), err)
+	}
+	return err
+}
+
+// lastGraph is the graph of the last build, which is kept up to date by
+// the rebuilds of single files, so that the site isn't parsed again.
+var lastGraph *kaguya.Graph
+
+// buildFile builds a single input file right away, without the pools, along
+// with the pages whose backlinks or neighbours have changed because of it.
+func buildFile(conf *alpha.DarknessConfig, inputFilename yunyun.FullPathFile) error {
+	parsers := parse.BuildParsers(conf)
+	exporter := export.BuildExporter(conf)
+	control := &makima.Control{
+		Conf:          conf,
+		Parser:        parsers.For(inputFilename),
+		Exporter:      exporter,
+		Graph:         lastGraph,
+		InputFilename: inputFilename,
+	}
+	if control.Parser == nil {
+		return fmt.Errorf("no parser for %s", inputFilename)
+	}
+	_, err := control.Read()
+	if err == nil {
+		_, err = control.Parse()
+	}
+	if err == nil {
+		// The page might link elsewhere or move around now, so bring the
+		// graph up to date before exporting it.
+		previous := lastGraph
+		lastGraph = lastGraph.With(control.Page)
+		control.Graph = lastGraph
+		err = control.Export().Write()
+		for _, file := range lastGraph.Changed(previous) {
+			if file == control.Page.File {
+				continue
+			}
+			dependent := conf.Runtime.WorkDir.Join(file)
+			rebuildFile(&makima.Control{
+				Conf:          conf,
+				Parser:        parsers.For(dependent),
+				Exporter:      exporter,
+				Graph:         lastGraph,
+				InputFilename: dependent,
+			})
+		}
+	}
+	// The failures are recorded, so report them with everything else.
+	if err != nil {
+		puck.Logger.Debug("Building", "input", inputFilename, "err", err)
+	}
+	return reportDiagnostics()
+}
+
+// rebuildFile builds the input of the control with its graph as it is.
+func rebuildFile(control *makima.Control) {
+	if control.Parser == nil {
+		return
+	}
+	_, err := control.Read()
+	if err == nil {
+		_, err = control.Parse()
+	}
+	if err == nil {
+		err = control.Export().Write()
+	}
+	if err != nil {
+		puck.Logger.Debug("Rebuilding", "input", control.InputFilename, "err", err)
+	}
+}
+
+// buildGraph returns the links between all the pages if backlinks or
+// navigation are enabled, nil otherwise.
+func buildGraph(conf *alpha.DarknessConfig) *kaguya.Graph {
+	if !conf.Website.Backlinks && !conf.Website.Navigation {
+		return nil
+	}
+	graph := kaguya.BuildGraph(conf, hizuru.BuildPagesSimple(conf, nil))
+	// The pages' problems will be reported by the build itself.
+	misaka.TakeDiagnostics()
+	return graph
+}
+
+// reportDiagnostics prints the problems recorded since the last report and
+// returns an error if anything failed, which includes warnings with `-strict`.
+func reportDiagnostics() error {
+	diagnostics := misaka.TakeDiagnostics()
+	diagnostics.Sort()
+	warnings, failures := 0, 0
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintln(os.Stderr, diagnostic)
+		if diagnostic.Severity == yunyun.SeverityError {
+			failures++
+		} else {
+			warnings++
+		}
+	}
+	if len(diagnostics) > 0 {
+		fmt.Fprintf(os.Stderr, "%d warning(s), %d error(s)\n", warnings, failures)
+	}
+	if kuroko.Strict && warnings > 0 {
+		return fmt.Errorf("%d error(s) and %d warning(s) in strict mode", failures, warnings)
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d error(s)", failures)
+	}
+	return nil
 }
 
 // logErrors is a helper function that logs errors from a pool. It is meant to be
 // used as a goroutine.
 func logErrors[T any](name string, vv chan komi.PoolError[T]) {
 	for v := range vv {
-		if v.Error != nil {
+		// Unchanged inputs are not failures.
+		if v.Error != nil && !errors.Is(v.Error, makima.ErrUnchanged) {
 			puck.Logger.Error("job failed", "err", v.Error, "pool", name)
 		}
 	}
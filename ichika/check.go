@@ -0,0 +1,25 @@
+package ichika
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/ichika/kazuma"
+)
+
+// CheckCommandFunc parses the entire directory without building it and
+// reports the problems, exits with a non-zero code if anything failed.
+func CheckCommandFunc() {
+	cmd := darknessFlagset(checkCommand)
+	conf := alpha.BuildConfig(getAlphaOptions(cmd))
+	checked := kazuma.Check(conf)
+	err := reportDiagnostics()
+	fmt.Printf("Checked %d pages\n", checked)
+	if err != nil {
+		puck.Logger.Error("Checking", "err", err)
+		os.Exit(1)
+	}
+	fmt.Println("farewell")
+}
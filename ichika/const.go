@@ -12,6 +12,7 @@ const (
 	misaCommand        DarknessCommand = `misa`
 	lalatinaCommand    DarknessCommand = `lalatina`
 	aquaCommand        DarknessCommand = `aqua`
+	checkCommand       DarknessCommand = `check`
 )
 
 // CommandFuncs maps supplied darkness command to the function
@@ -25,6 +26,7 @@ var CommandFuncs = map[DarknessCommand]func(){
 	misaCommand:        MisaCommandFunc,
 	lalatinaCommand:    LalatinaCommandFunc,
 	aquaCommand:        AquaCommandFunc,
+	checkCommand:       CheckCommandFunc,
 
 	// All the help commands
 	`-h`:     HelpCommandFunc,
@@ -25,6 +25,8 @@ func getAlphaOptions(cmd *flag.FlagSet) alpha.Options {
 	cmd.BoolVar(&kuroko.Akaneless, "akaneless", false, "skip akane processing")
 	cmd.BoolVar(&kuroko.Force, "force", false, "force post-processing (akane or misa)")
 	cmd.BoolVar(&kuroko.BuildReport, "build-report", false, "produce a build report")
+	cmd.BoolVar(&kuroko.NoCache, "no-cache", false, "rebuild all pages, ignoring the build cache")
+	cmd.BoolVar(&kuroko.Strict, "strict", false, "treat warnings as failures")
 	if len(os.Args) < 2 {
 		puck.Logger.Fatalf("no command specified")
 	}
@@ -0,0 +1,150 @@
+package frieren
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/rei"
+)
+
+const (
+	// cacheFilename is where the cache is stored in the work directory.
+	cacheFilename = ".darkness/cache.json"
+)
+
+// Entry is a single remembered build of an input.
+type Entry struct {
+	// Input is the hash of the input file's contents.
+	Input string `json:"input"`
+	// Output is the output filename the input was built into.
+	Output string `json:"output"`
+	// Diagnostics are the warnings found while building the input,
+	// which are reported again every time the input is skipped.
+	Diagnostics yunyun.Diagnostics `json:"diagnostics,omitempty"`
+}
+
+// Cache remembers what inputs were built into, such that unchanged
+// inputs can be skipped.
+type Cache struct {
+	// Key is the hash of the config and darkness version, all entries
+	// are forgotten if it changes.
+	Key string `json:"key"`
+	// Entries are the remembered builds by the relative input path.
+	Entries map[yunyun.RelativePathFile]Entry `json:"entries"`
+
+	// seen are the inputs that were looked up or stored in this build.
+	seen map[yunyun.RelativePathFile]struct{}
+	// skipped is the number of unchanged inputs in this build.
+	skipped atomic.Int64
+	// lock guards the entries, as pools are concurrent.
+	lock sync.Mutex
+	// filename is the full path of the cache file.
+	filename string
+}
+
+// Load loads the cache from the work directory, it returns an empty
+// cache if there's none or it was built with a different config.
+func Load(conf *alpha.DarknessConfig) *Cache {
+	cache := &Cache{
+		Key:      buildKey(conf),
+		Entries:  map[yunyun.RelativePathFile]Entry{},
+		seen:     map[yunyun.RelativePathFile]struct{}{},
+		filename: string(conf.Runtime.WorkDir.Join(cacheFilename)),
+	}
+	data, err := os.ReadFile(filepath.Clean(cache.filename))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("Reading cache, starting fresh", "err", err)
+		}
+		return cache
+	}
+	stored := &Cache{}
+	if err := json.Unmarshal(data, stored); err != nil {
+		logger.Warn("Decoding cache, starting fresh", "err", err)
+		return cache
+	}
+	if stored.Key != cache.Key {
+		logger.Debug("Config or darkness changed, starting fresh")
+		return cache
+	}
+	if stored.Entries != nil {
+		cache.Entries = stored.Entries
+	}
+	return cache
+}
+
+// Unchanged returns true if the input with the given hash has been
+// built before and its output still exists, with the warnings found
+// when it was built.
+func (c *Cache) Unchanged(input yunyun.RelativePathFile, hash string) (yunyun.Diagnostics, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.seen[input] = struct{}{}
+	entry, ok := c.Entries[input]
+	if !ok || entry.Input != hash {
+		return nil, false
+	}
+	exists, _ := rei.FileExists(entry.Output)
+	if !exists {
+		return nil, false
+	}
+	c.skipped.Add(1)
+	return entry.Diagnostics, true
+}
+
+// Skipped returns the number of unchanged inputs found so far.
+func (c *Cache) Skipped() int64 {
+	return c.skipped.Load()
+}
+
+// Forget drops all the entries, so that everything gets rebuilt.
+func (c *Cache) Forget() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Entries = map[yunyun.RelativePathFile]Entry{}
+}
+
+// Store remembers that the input with the given hash was built into the
+// output with the given warnings.
+func (c *Cache) Store(input yunyun.RelativePathFile, hash, output string, diagnostics yunyun.Diagnostics) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.seen[input] = struct{}{}
+	c.Entries[input] = Entry{Input: hash, Output: output, Diagnostics: diagnostics}
+}
+
+// Save writes the cache to disk, forgetting inputs that were not seen.
+func (c *Cache) Save() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for input := range c.Entries {
+		if _, ok := c.seen[input]; !ok {
+			delete(c.Entries, input)
+		}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("encoding cache: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.filename), 0o750); err != nil {
+		return fmt.Errorf("creating cache directory: %v", err)
+	}
+	if err := os.WriteFile(c.filename, data, 0o600); err != nil {
+		return fmt.Errorf("writing cache %s: %v", c.filename, err)
+	}
+	return nil
+}
+
+// Hash returns the hash of the input's contents.
+func Hash(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
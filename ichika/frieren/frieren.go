@@ -0,0 +1,6 @@
+package frieren
+
+import "github.com/thecsw/darkness/emilia/puck"
+
+// logger is the logger for Frieren.
+var logger = puck.NewLogger("Frieren 🧝", puck.InfoLevel)
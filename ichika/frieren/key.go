@@ -0,0 +1,70 @@
+package frieren
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// buildKey hashes everything outside of the inputs that changes the outputs,
+// which is the config file, the theme, the runtime options, and darkness herself.
+func buildKey(conf *alpha.DarknessConfig) string {
+	config, err := os.ReadFile(filepath.Clean(conf.Runtime.ConfigPath))
+	if err != nil {
+		logger.Warn("Reading config for the cache key", "err", err)
+	}
+	return Hash(fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%t\n%s",
+		config,
+		theme(conf),
+		conf.Url,
+		conf.Project.Output,
+		conf.Runtime.OutputDir,
+		conf.Runtime.VendorGalleries,
+		version(),
+	))
+}
+
+// theme returns the contents of the website's own html templates.
+func theme(conf *alpha.DarknessConfig) string {
+	directory := conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(puck.ThemeDirectory))
+	templates, err := filepath.Glob(filepath.Join(string(directory), "*.html"))
+	if err != nil {
+		logger.Warn("Finding theme for the cache key", "err", err)
+	}
+	result := ""
+	for _, template := range templates {
+		data, err := os.ReadFile(filepath.Clean(template))
+		if err != nil {
+			logger.Warn("Reading theme for the cache key", "path", template, "err", err)
+		}
+		result += template + "\n" + string(data) + "\n"
+	}
+	return result
+}
+
+// version returns the version of the running darkness, where the
+// executable's size and modification time catch development builds.
+func version() string {
+	result := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		result = info.Main.Version
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+				result += " " + setting.Value
+			}
+		}
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return result
+	}
+	if stat, err := os.Stat(executable); err == nil {
+		result += fmt.Sprintf(" %d %d", stat.Size(), stat.ModTime().UnixNano())
+	}
+	return result
+}
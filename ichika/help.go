@@ -18,6 +18,7 @@ creating it with new followed by the directory name
 Here are the commands you can use, -help is supported:
   build - build the entire directory
   serve - build HTTP and serve them
+  check - look for broken links and other problems
   megumin - blow up the directory!!
   clean - megumin but super boring
   misa - supercharge your website
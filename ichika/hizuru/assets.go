@@ -0,0 +1,75 @@
+package hizuru
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/karrick/godirwalk"
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/rei"
+)
+
+// FindAssets finds all the files that the built site needs next to the outputs,
+// which is everything that is not an input, an output, or a hidden file, and
+// that the project's publish patterns let through.
+func FindAssets(conf *alpha.DarknessConfig) []yunyun.FullPathFile {
+	assets := make([]yunyun.FullPathFile, 0, 64)
+	configPath := filepath.Clean(string(conf.Runtime.WorkDir.JoinGeneric(filepath.Base(conf.Runtime.ConfigPath))))
+	if err := godirwalk.Walk(string(conf.Runtime.WorkDir), &godirwalk.Options{
+		ErrorCallback: func(osPathname string, err error) godirwalk.ErrorAction {
+			conf.Runtime.Logger.Errorf("traversing %s: %v", osPathname, err)
+			return godirwalk.SkipNode
+		},
+		Unsorted: true,
+		Callback: func(osPathname string, de *godirwalk.Dirent) error {
+			// Skip the built site itself, the theme's templates, and anything
+			// hidden, like `.git`.
+			if (osPathname != string(conf.Runtime.WorkDir) && strings.HasPrefix(de.Name(), ".")) ||
+				conf.IsOutputDirectory(osPathname) || conf.IsThemeDirectory(osPathname) {
+				if de.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if de.IsDir() {
+				return nil
+			}
+			if conf.Project.ExcludeEnabled && conf.Project.ExcludeRegex.MatchString(osPathname) {
+				return nil
+			}
+			// Inputs are built, not copied, and so is the config.
+			if conf.Project.Input.HasFile(osPathname) || filepath.Clean(osPathname) == configPath {
+				return nil
+			}
+			// Outputs from building in place are stale, skip them.
+			if filepath.Ext(osPathname) == conf.Project.Output && hasInputSibling(conf, osPathname) {
+				return nil
+			}
+			relPath, err := filepath.Rel(string(conf.Runtime.WorkDir), osPathname)
+			if err != nil {
+				return fmt.Errorf("finding relative path of %s to %s: %v", osPathname, conf.Runtime.WorkDir, err)
+			}
+			if !conf.ShouldPublish(yunyun.RelativePathFile(relPath)) {
+				return nil
+			}
+			assets = append(assets, conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(relPath)))
+			return nil
+		},
+	}); err != nil {
+		conf.Runtime.Logger.Errorf("root traversal: %v", err)
+	}
+	return assets
+}
+
+// hasInputSibling returns true if the output file has an input it was built from.
+func hasInputSibling(conf *alpha.DarknessConfig, outputFilename string) bool {
+	trimmed := strings.TrimSuffix(outputFilename, filepath.Ext(outputFilename))
+	for _, ext := range conf.Project.Input {
+		if exists, _ := rei.FileExists(trimmed + ext); exists {
+			return true
+		}
+	}
+	return false
+}
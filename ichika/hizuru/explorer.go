@@ -1,6 +1,7 @@
 package hizuru
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -9,16 +10,20 @@ import (
 	"github.com/karrick/godirwalk"
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/ichika/misaka"
 	"github.com/thecsw/darkness/parse"
 	"github.com/thecsw/darkness/yunyun"
 	g "github.com/thecsw/gana"
 	"github.com/thecsw/rei"
 )
 
-// FindFilesByExt finds all files with a given extension.
+// FindFilesByExt finds all files with any of the input extensions.
 func FindFilesByExt(conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPathFile) {
 	// We don't need a concurrent map because we're only using it in a single goroutine.
 	pathDedupe := map[string]struct{}{}
+	// outputs remember which input is written where, as inputs of
+	// different formats, like `x.org` and `x.md`, write the same page.
+	outputs := map[string]string{}
 	if err := godirwalk.Walk(string(conf.Runtime.WorkDir), &godirwalk.Options{
 		ErrorCallback: func(osPathname string, err error) godirwalk.ErrorAction {
 			conf.Runtime.Logger.Errorf("traversing %s: %v", osPathname, err)
@@ -26,7 +31,11 @@ func FindFilesByExt(conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPat
 		},
 		Unsorted: true,
 		Callback: func(osPathname string, de *godirwalk.Dirent) error {
-			if filepath.Ext(osPathname) != conf.Project.Input || strings.HasPrefix(filepath.Base(osPathname), ".") {
+			// Never look for inputs in the built site.
+			if de.IsDir() && conf.IsOutputDirectory(osPathname) {
+				return filepath.SkipDir
+			}
+			if !conf.Project.Input.HasFile(osPathname) || strings.HasPrefix(filepath.Base(osPathname), ".") {
 				return nil
 			}
 			if (conf.Project.ExcludeEnabled && conf.Project.ExcludeRegex.MatchString(osPathname)) ||
@@ -37,12 +46,23 @@ func FindFilesByExt(conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPat
 			if err != nil {
 				return fmt.Errorf("finding relative path of %s to %s: %v", osPathname, conf.Runtime.WorkDir, err)
 			}
-			// If we haven't seen this path before, add it to the channel.
-			if _, seen := pathDedupe[relPath]; !seen {
-				inputFiles <- conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(relPath))
+			// If we have seen this path before, skip it.
+			if _, seen := pathDedupe[relPath]; seen {
+				return nil
 			}
 			// Mark this path as seen.
 			pathDedupe[relPath] = struct{}{}
+			inputFile := conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(relPath))
+			output := conf.InputFilenameToOutput(inputFile)
+			if other, taken := outputs[output]; taken {
+				first, second := min(other, relPath), max(other, relPath)
+				found := yunyun.Diagnostics{}
+				found.Fail(yunyun.RelativePathFile(relPath), "%s and %s are both built into %s", first, second, output)
+				misaka.RecordDiagnostics(found...)
+				return nil
+			}
+			outputs[output] = relPath
+			inputFiles <- inputFile
 			return nil
 		},
 	}); err != nil {
@@ -81,7 +101,7 @@ func findFilesByExtSimpleDirs(conf *alpha.DarknessConfig, dirs []string) []yunyu
 func BuildPagesSimple(conf *alpha.DarknessConfig, dirs []string) []*yunyun.Page {
 	inputFilenames := findFilesByExtSimpleDirs(conf, dirs)
 	pages := make([]*yunyun.Page, 0, len(inputFilenames))
-	parser := parse.BuildParser(conf)
+	parsers := parse.BuildParsers(conf)
 	for _, inputFilename := range inputFilenames {
 		bundleOption := openFile(inputFilename)
 		if bundleOption.IsNone() {
@@ -93,9 +113,17 @@ func BuildPagesSimple(conf *alpha.DarknessConfig, dirs []string) []*yunyun.Page
 			puck.Logger.Printf("reading file %s: %v", inputFilename, err)
 			continue
 		}
-		page := parser.Do(conf.Runtime.WorkDir.Rel(bundle.First), string(data))
+		page, err := parsers.For(inputFilename).Do(conf.Runtime.WorkDir.Rel(bundle.First), string(data))
+		if err != nil {
+			logger.Debug("Parsing", "input", conf.Runtime.WorkDir.Rel(bundle.First), "err", err)
+			misaka.RecordFailure(conf.Runtime.WorkDir.Rel(bundle.First), err)
+			// Pages with only warnings are still good.
+			if diagnostics := (yunyun.Diagnostics{}); !errors.As(err, &diagnostics) || diagnostics.HasErrors() {
+				continue
+			}
+		}
 		if page == nil {
-			logger.Warnf("Parser produced a nil page", "input", conf.Runtime.WorkDir.Rel(bundle.First))
+			logger.Warn("Parser produced a nil page", "input", conf.Runtime.WorkDir.Rel(bundle.First))
 			continue
 		}
 		pages = append(pages, page)
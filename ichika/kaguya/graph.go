@@ -0,0 +1,182 @@
+package kaguya
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// Graph is the links between the published pages of the website.
+type Graph struct {
+	// Nodes are the pages, sorted by their locations.
+	Nodes []Node `json:"nodes"`
+	// Edges are the links between the pages, sorted by their ends.
+	Edges []Edge `json:"edges"`
+
+	// backlinks are the pages linking to the location.
+	backlinks map[yunyun.RelativePathDir][]*yunyun.Page
+	// navigation is the way around the location.
+	navigation map[yunyun.RelativePathDir]*yunyun.Navigation
+
+	// conf is the config the graph was built with.
+	conf *alpha.DarknessConfig
+	// pages are the pages the graph was built from, in the same order.
+	pages []*yunyun.Page
+}
+
+// Node is a single page of the graph.
+type Node struct {
+	// ID is the page's location.
+	ID string `json:"id"`
+	// Title is the page's title.
+	Title string `json:"title"`
+	// Url is the full url of the page.
+	Url string `json:"url"`
+}
+
+// Edge is a link from one page to another.
+type Edge struct {
+	// Source is the location of the linking page.
+	Source string `json:"source"`
+	// Target is the location of the linked page.
+	Target string `json:"target"`
+}
+
+// BuildGraph follows the links of all the pages, where drafts and links
+// to the same page are left out.
+func BuildGraph(conf *alpha.DarknessConfig, pages []*yunyun.Page) *Graph {
+	s := NewSite(conf, pages)
+	g := &Graph{
+		Nodes:      make([]Node, 0, len(pages)),
+		Edges:      make([]Edge, 0, len(pages)),
+		backlinks:  make(map[yunyun.RelativePathDir][]*yunyun.Page),
+		navigation: buildNavigation(pages),
+		conf:       conf,
+		pages:      pages,
+	}
+	for _, page := range pages {
+		if page.Accoutrement.Draft.IsEnabled() {
+			continue
+		}
+		g.Nodes = append(g.Nodes, Node{
+			ID:    string(page.Location),
+			Title: yunyun.RemoveFormatting(page.Title),
+			Url:   string(conf.Runtime.Join(yunyun.RelativePathFile(page.Location))),
+		})
+		seen := make(map[yunyun.RelativePathDir]bool)
+		for _, link := range PageLinks(page) {
+			target, _, internal := s.ResolveLink(page, link)
+			if !internal || len(target) < 1 {
+				continue
+			}
+			linked := s.FindPage(target)
+			if linked == nil || linked == page || linked.Accoutrement.Draft.IsEnabled() || seen[linked.Location] {
+				continue
+			}
+			seen[linked.Location] = true
+			g.Edges = append(g.Edges, Edge{Source: string(page.Location), Target: string(linked.Location)})
+			g.backlinks[linked.Location] = append(g.backlinks[linked.Location], page)
+		}
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].Source != g.Edges[j].Source {
+			return g.Edges[i].Source < g.Edges[j].Source
+		}
+		return g.Edges[i].Target < g.Edges[j].Target
+	})
+	for _, linking := range g.backlinks {
+		sort.Slice(linking, func(i, j int) bool { return linking[i].Title < linking[j].Title })
+	}
+	return g
+}
+
+// With returns the graph built again from the same pages, where the page
+// replaces the one of its input file, so that nothing is parsed again.
+func (g *Graph) With(page *yunyun.Page) *Graph {
+	if g == nil {
+		return nil
+	}
+	pages := make([]*yunyun.Page, 0, len(g.pages)+1)
+	replaced := false
+	for _, other := range g.pages {
+		if other.File == page.File {
+			other, replaced = snapshot(page), true
+		}
+		pages = append(pages, other)
+	}
+	if !replaced {
+		pages = append(pages, snapshot(page))
+	}
+	return BuildGraph(g.conf, pages)
+}
+
+// Changed returns the input files of the pages whose backlinks or
+// neighbours are different from the ones in the previous graph.
+func (g *Graph) Changed(previous *Graph) []yunyun.RelativePathFile {
+	if g == nil {
+		return nil
+	}
+	changed := make([]yunyun.RelativePathFile, 0, 4)
+	for _, page := range g.pages {
+		if g.Fingerprint(page.Location) != previous.Fingerprint(page.Location) {
+			changed = append(changed, page.File)
+		}
+	}
+	return changed
+}
+
+// snapshot returns a copy of the page and its contents, as the pages
+// are enriched in place when they are exported.
+func snapshot(page *yunyun.Page) *yunyun.Page {
+	copied := *page
+	copied.Contents = make(yunyun.Contents, 0, len(page.Contents))
+	for _, content := range page.Contents {
+		contentCopy := *content
+		copied.Contents = append(copied.Contents, &contentCopy)
+	}
+	return &copied
+}
+
+// Backlinks returns the pages that link to the location, sorted by titles.
+func (g *Graph) Backlinks(location yunyun.RelativePathDir) []yunyun.Backlink {
+	if g == nil {
+		return nil
+	}
+	backlinks := make([]yunyun.Backlink, 0, len(g.backlinks[location]))
+	for _, page := range g.backlinks[location] {
+		backlinks = append(backlinks, yunyun.Backlink{Title: page.Title, Location: page.Location})
+	}
+	return backlinks
+}
+
+// Navigation returns the way to the pages around the location, nil if
+// it has no neighbours.
+func (g *Graph) Navigation(location yunyun.RelativePathDir) *yunyun.Navigation {
+	if g == nil {
+		return nil
+	}
+	return g.navigation[location]
+}
+
+// Fingerprint returns the backlinks and the neighbours of the location
+// as a string, which changes whenever any of them do.
+func (g *Graph) Fingerprint(location yunyun.RelativePathDir) string {
+	var fingerprint strings.Builder
+	for _, backlink := range g.Backlinks(location) {
+		fingerprint.WriteString(string(backlink.Location) + "\x00" + backlink.Title + "\x00")
+	}
+	if navigation := g.Navigation(location); navigation != nil {
+		fingerprint.WriteString(fmt.Sprintf("%s\x00%d\x00%d\x00", navigation.Series, navigation.Part, navigation.Parts))
+		for _, neighbour := range []*yunyun.Backlink{navigation.Previous, navigation.Next} {
+			if neighbour != nil {
+				fingerprint.WriteString(string(neighbour.Location) + "\x00" + neighbour.Title)
+			}
+			fingerprint.WriteString("\x00")
+		}
+	}
+	return fingerprint.String()
+}
@@ -0,0 +1,78 @@
+package kaguya
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// buildNavigation chains the pages of every series and the dated pages
+// of every directory, from the oldest to the newest.
+func buildNavigation(pages []*yunyun.Page) map[yunyun.RelativePathDir]*yunyun.Navigation {
+	series := make(map[string][]*yunyun.Page)
+	directories := make(map[yunyun.RelativePathDir][]*yunyun.Page)
+	for _, page := range pages {
+		// Drafts and the root page are nobody's neighbours.
+		if page.Accoutrement.Draft.IsEnabled() || page.Location == "." {
+			continue
+		}
+		if len(page.Series) > 0 {
+			series[page.Series] = append(series[page.Series], page)
+			continue
+		}
+		if _, dated := narumi.ConvertHoloscene(page.Date); dated {
+			parent := yunyun.RelativePathDir(filepath.Dir(string(page.Location)))
+			directories[parent] = append(directories[parent], page)
+		}
+	}
+	navigation := make(map[yunyun.RelativePathDir]*yunyun.Navigation, len(pages))
+	for name, chain := range series {
+		chainPages(navigation, name, chain)
+	}
+	for _, chain := range directories {
+		chainPages(navigation, "", chain)
+	}
+	return navigation
+}
+
+// chainPages sorts the pages by their dates and links each to the ones
+// around it, where the pages with no dates go last.
+func chainPages(navigation map[yunyun.RelativePathDir]*yunyun.Navigation, series string, pages []*yunyun.Page) {
+	// A single page has nowhere to go.
+	if len(pages) < 2 {
+		return
+	}
+	dates := make(map[*yunyun.Page]time.Time, len(pages))
+	for _, page := range pages {
+		if date, dated := narumi.ConvertHoloscene(page.Date); dated {
+			dates[page] = date
+		}
+	}
+	sort.SliceStable(pages, func(i, j int) bool {
+		left, leftDated := dates[pages[i]]
+		right, rightDated := dates[pages[j]]
+		if leftDated != rightDated {
+			return leftDated
+		}
+		if !left.Equal(right) {
+			return left.Before(right)
+		}
+		if pages[i].Title != pages[j].Title {
+			return pages[i].Title < pages[j].Title
+		}
+		return pages[i].Location < pages[j].Location
+	})
+	for i, page := range pages {
+		current := &yunyun.Navigation{Series: series, Part: i + 1, Parts: len(pages)}
+		if i > 0 {
+			current.Previous = &yunyun.Backlink{Title: pages[i-1].Title, Location: pages[i-1].Location}
+		}
+		if i < len(pages)-1 {
+			current.Next = &yunyun.Backlink{Title: pages[i+1].Title, Location: pages[i+1].Location}
+		}
+		navigation[page.Location] = current
+	}
+}
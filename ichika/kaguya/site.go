@@ -0,0 +1,112 @@
+package kaguya
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// Site is every page of the website, so that links can be followed.
+type Site struct {
+	// conf is the config of the website.
+	conf *alpha.DarknessConfig
+	// pages are the pages by their input files.
+	pages map[yunyun.RelativePathFile]*yunyun.Page
+}
+
+// NewSite returns the site of the given pages.
+func NewSite(conf *alpha.DarknessConfig, pages []*yunyun.Page) *Site {
+	s := &Site{
+		conf:  conf,
+		pages: make(map[yunyun.RelativePathFile]*yunyun.Page, len(pages)),
+	}
+	for _, page := range pages {
+		s.pages[page.File] = page
+	}
+	return s
+}
+
+// PageLinks returns all the links found in the page's contents, where
+// galleries are left out, as they only link to their images.
+func PageLinks(page *yunyun.Page) []string {
+	links := make([]string, 0, 16)
+	addLinks := func(text string) {
+		for _, link := range yunyun.ExtractLinks(text) {
+			links = append(links, link.Link)
+		}
+	}
+	for _, content := range page.Contents {
+		switch {
+		case content.IsLink():
+			links = append(links, content.Link)
+		case content.IsParagraph():
+			addLinks(content.Paragraph)
+		case content.IsHeading():
+			addLinks(content.Heading)
+		case content.IsAttentionBlock():
+			addLinks(content.AttentionText)
+		case content.IsTable():
+			for _, row := range content.Table {
+				for _, cell := range row {
+					addLinks(cell)
+				}
+			}
+		case (content.IsList() || content.IsListNumbered()) && !content.IsGallery():
+			for _, item := range content.List {
+				addLinks(item.Text)
+			}
+		}
+	}
+	return links
+}
+
+// ResolveLink returns the link's target relative to the work directory and
+// its anchor, where the target is empty for the same page. The last return
+// value is false if the link leaves the website.
+func (s *Site) ResolveLink(page *yunyun.Page, link string) (string, string, bool) {
+	link = strings.TrimSpace(link)
+	// Absolute links to our own website are internal too.
+	if len(s.conf.Url) > 0 && strings.HasPrefix(link, s.conf.Url) {
+		link = "/" + strings.TrimPrefix(strings.TrimPrefix(link, s.conf.Url), "/")
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "", "", false
+	}
+	target := parsed.Path
+	switch {
+	// Orgmode likes its `file:` links.
+	case parsed.Scheme == "file":
+		target = parsed.Opaque + parsed.Path
+	case len(parsed.Scheme) > 0 || len(parsed.Host) > 0:
+		return "", "", false
+	}
+	if len(target) < 1 {
+		return "", parsed.Fragment, true
+	}
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/"), parsed.Fragment, true
+	}
+	return path.Join(string(page.Location), target), parsed.Fragment, true
+}
+
+// FindPage returns the page that the target is built from, nil if none.
+func (s *Site) FindPage(target string) *yunyun.Page {
+	target = strings.TrimSuffix(target, "/")
+	candidates := []string{target}
+	for _, ext := range s.conf.Project.Input {
+		candidates = append(candidates,
+			path.Join(target, "index"+ext),
+			strings.TrimSuffix(target, s.conf.Project.Output)+ext,
+		)
+	}
+	for _, candidate := range candidates {
+		if page, ok := s.pages[yunyun.RelativePathFile(path.Clean(candidate))]; ok {
+			return page
+		}
+	}
+	return nil
+}
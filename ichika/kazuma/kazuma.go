@@ -0,0 +1,89 @@
+package kazuma
+
+import (
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/emilia/rem"
+	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/ichika/kaguya"
+	"github.com/thecsw/darkness/ichika/misaka"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// logger is the logger for Kazuma.
+var logger = puck.NewLogger("Kazuma 🗡️ ", puck.InfoLevel)
+
+// site is every page of the website, so that links can be followed.
+type site struct {
+	// conf is the config of the website.
+	conf *alpha.DarknessConfig
+	// links follows the links between the pages.
+	links *kaguya.Site
+	// anchors are the heading anchors of the pages by their input files.
+	anchors map[yunyun.RelativePathFile]map[string]bool
+}
+
+// Check parses every page of the website and records the problems with
+// misaka, returns the number of pages checked.
+func Check(conf *alpha.DarknessConfig) int {
+	pages := hizuru.BuildPagesSimple(conf, nil)
+	s := &site{
+		conf:    conf,
+		links:   kaguya.NewSite(conf, pages),
+		anchors: make(map[yunyun.RelativePathFile]map[string]bool, len(pages)),
+	}
+	for _, page := range pages {
+		s.anchors[page.File] = s.checkAnchors(page)
+	}
+	for _, page := range pages {
+		logger.Debug("Checking", "page", page.File)
+		s.checkPage(page)
+	}
+	// Pages are exported last, as that enriches them.
+	for _, page := range pages {
+		s.checkExternalRequests(page)
+	}
+	return len(pages)
+}
+
+// checkAnchors returns the heading anchors of the page and records the
+// headings that were given the same custom ids.
+func (s *site) checkAnchors(page *yunyun.Page) map[string]bool {
+	diagnostics := yunyun.Diagnostics{}
+	anchors := map[string]bool{}
+	headings := page.Contents.Headings()
+	ids := html.HeadingIDs(headings)
+	for _, heading := range headings {
+		anchor := ids[heading]
+		if anchors[anchor] {
+			diagnostics.Warn(page.File, "heading %q has the same custom id #%s as another heading", heading.Heading, anchor)
+		}
+		anchors[anchor] = true
+	}
+	misaka.RecordDiagnostics(diagnostics...)
+	return anchors
+}
+
+// checkPage records the problems of the page.
+func (s *site) checkPage(page *yunyun.Page) {
+	diagnostics := yunyun.Diagnostics{}
+	if !page.HasTitle() {
+		diagnostics.Warn(page.File, "page has no title")
+	}
+	if page.HasDate() && strings.Contains(page.Date, "H.E.") {
+		if err := narumi.ValidateHoloscene(page.Date); err != nil {
+			diagnostics.Fail(page.File, "invalid holoscene date: %v", err)
+		}
+	}
+	for _, item := range rem.MissingGalleryImages(s.conf, page) {
+		diagnostics.Fail(page.File, "missing gallery image %s", yunyun.JoinRelativePaths(item.Path, item.Item))
+	}
+	for _, link := range kaguya.PageLinks(page) {
+		s.checkLink(page, link, &diagnostics)
+	}
+	misaka.RecordDiagnostics(diagnostics...)
+}
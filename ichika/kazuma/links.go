@@ -0,0 +1,36 @@
+package kazuma
+
+import (
+	"os"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// checkLink records the problem if the link is internal and points
+// to a page, heading, or file that doesn't exist.
+func (s *site) checkLink(page *yunyun.Page, link string, diagnostics *yunyun.Diagnostics) {
+	target, anchor, internal := s.links.ResolveLink(page, link)
+	if !internal {
+		return
+	}
+	// Links to the headings of the same page.
+	if len(target) < 1 {
+		if len(anchor) > 0 && !s.anchors[page.File][anchor] {
+			diagnostics.Fail(page.File, "link %s points to a heading that doesn't exist", link)
+		}
+		return
+	}
+	if linked := s.links.FindPage(target); linked != nil {
+		if len(anchor) > 0 && !s.anchors[linked.File][anchor] {
+			diagnostics.Fail(page.File, "link %s points to a heading that doesn't exist in %s", link, linked.File)
+		}
+		if linked.Accoutrement.Draft.IsEnabled() && !page.Accoutrement.Draft.IsEnabled() {
+			diagnostics.Warn(page.File, "link %s points to the draft %s", link, linked.File)
+		}
+		return
+	}
+	// Everything else should at least be a file, like an image.
+	if _, err := os.Stat(string(s.conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(target)))); err != nil {
+		diagnostics.Fail(page.File, "link %s points to %s, which doesn't exist", link, target)
+	}
+}
@@ -0,0 +1,106 @@
+package kazuma
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/ichika/chiho"
+	"github.com/thecsw/darkness/ichika/misaka"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+var (
+	// requestingTagRegexp finds the tags that make browsers request their urls.
+	requestingTagRegexp = regexp.MustCompile(`(?is)<(script|img|iframe|audio|video|source|track|embed|link)\b[^>]*>`)
+	// requestingAttributeRegexp finds the url attributes of the tags.
+	requestingAttributeRegexp = regexp.MustCompile(`(?is)\s(src|href|poster|srcset)\s*=\s*["']([^"']*)["']`)
+	// linkRelRegexp finds the relation of the link tags.
+	linkRelRegexp = regexp.MustCompile(`(?is)\srel\s*=\s*["']([^"']*)["']`)
+	// templateRegexp finds the templates, which browsers don't load,
+	// like the click-to-load embeds of the offline websites.
+	templateRegexp = regexp.MustCompile(`(?is)<template\b.*?</template>`)
+)
+
+// requestingLinkRels are the link relations that browsers download.
+var requestingLinkRels = []string{"stylesheet", "icon", "preload", "prefetch", "modulepreload", "manifest"}
+
+// checkExternalRequests records the remote urls that the exported page
+// still requests if the website is supposed to be offline.
+func (s *site) checkExternalRequests(page *yunyun.Page) {
+	if !s.conf.Website.Offline {
+		return
+	}
+	exported, err := io.ReadAll(html.ExporterHtml{Config: s.conf}.Do(chiho.EnrichPage(s.conf, page)))
+	if err != nil {
+		logger.Error("Exporting", "page", page.File, "err", err)
+		return
+	}
+	diagnostics := yunyun.Diagnostics{}
+	for _, request := range s.externalRequests(string(exported)) {
+		diagnostics.Warn(page.File, "page requests %s, which is not local", request)
+	}
+	misaka.RecordDiagnostics(diagnostics...)
+}
+
+// externalRequests returns the remote urls that the html requests.
+func (s *site) externalRequests(exported string) []string {
+	exported = templateRegexp.ReplaceAllString(exported, "")
+	requests := make([]string, 0, 4)
+	seen := map[string]bool{}
+	for _, tag := range requestingTagRegexp.FindAllStringSubmatch(exported, -1) {
+		if strings.EqualFold(tag[1], "link") && !isRequestingLink(tag[0]) {
+			continue
+		}
+		for _, attribute := range requestingAttributeRegexp.FindAllStringSubmatch(tag[0], -1) {
+			urls := []string{attribute[2]}
+			if strings.EqualFold(attribute[1], "srcset") {
+				urls = srcsetUrls(attribute[2])
+			}
+			for _, url := range urls {
+				if s.isExternal(url) && !seen[url] {
+					seen[url] = true
+					requests = append(requests, url)
+				}
+			}
+		}
+	}
+	return requests
+}
+
+// isRequestingLink tells whether the link tag's relation gets downloaded.
+func isRequestingLink(tag string) bool {
+	rel := linkRelRegexp.FindStringSubmatch(tag)
+	if rel == nil {
+		return false
+	}
+	for _, relation := range strings.Fields(strings.ToLower(rel[1])) {
+		for _, requesting := range requestingLinkRels {
+			if strings.Contains(relation, requesting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// srcsetUrls returns the urls of the srcset, without their sizes.
+func srcsetUrls(srcset string) []string {
+	urls := make([]string, 0, 2)
+	for _, candidate := range strings.Split(srcset, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			urls = append(urls, fields[0])
+		}
+	}
+	return urls
+}
+
+// isExternal tells whether the url is on another website.
+func (s *site) isExternal(url string) bool {
+	if strings.HasPrefix(url, s.conf.Url) {
+		return false
+	}
+	lower := strings.ToLower(url)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "//")
+}
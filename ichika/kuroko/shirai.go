@@ -48,4 +48,16 @@ var (
 	// project's .darkness directory with then files discovered, duration,
 	// and the output file that they reached.
 	BuildReport bool
+
+	// NoCache will rebuild every page, even if it hasn't changed
+	// since the last build, and refresh the build cache.
+	NoCache bool
+
+	// Cacheless disables the build cache completely, which is used
+	// when building somewhere temporary, like in `serve`.
+	Cacheless bool
+
+	// Strict turns the build warnings, like unknown options or
+	// missing gallery images, into failures.
+	Strict bool
 )
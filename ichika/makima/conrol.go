@@ -1,6 +1,7 @@
 package makima
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -8,26 +9,41 @@ import (
 
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/emilia/rem"
 	"github.com/thecsw/darkness/export"
 	"github.com/thecsw/darkness/ichika/chiho"
+	"github.com/thecsw/darkness/ichika/frieren"
+	"github.com/thecsw/darkness/ichika/kaguya"
 	"github.com/thecsw/darkness/ichika/misaka"
 	"github.com/thecsw/darkness/parse"
 	"github.com/thecsw/darkness/yunyun"
 )
 
+// ErrUnchanged is returned when reading an input that doesn't need to be rebuilt.
+var ErrUnchanged = errors.New("input is unchanged since the last build")
+
 // Control is the struct that is passed across darkness to build the site.
 type Control struct {
 	// Conf is the configuration for the site.
 	Conf *alpha.DarknessConfig
-	// Parser is the parser to use for the site.
+	// Parser is the parser to use for the input file.
 	Parser parse.Parser
 	// Exporter is the exporter to use for the site.
 	Exporter export.Exporter
+	// Cache is the build cache, nil if disabled.
+	Cache *frieren.Cache
+	// Graph is the links between the pages, nil if backlinks and
+	// navigation are disabled.
+	Graph *kaguya.Graph
 
 	// InputFilename is the filename of the input file.
 	InputFilename yunyun.FullPathFile
 	// Input is the input file's contents.
 	Input string
+	// InputHash is the hash of the input's contents.
+	InputHash string
+	// Diagnostics are the warnings found while building the input.
+	Diagnostics yunyun.Diagnostics
 
 	// Page is the parsed page.
 	Page *yunyun.Page
@@ -45,19 +61,44 @@ func (c *Control) Read() (Woof, error) {
 		RecordWithFile(misaka.RecordReadTime, c.InputFilename)
 	file, err := os.ReadFile(filepath.Clean(string(c.InputFilename)))
 	if err != nil {
-		return nil, fmt.Errorf("reading input file %s: %v", c.InputFilename, err)
+		return nil, c.fail(fmt.Errorf("reading input file %s: %v", c.InputFilename, err))
 	}
 	c.Input = string(file)
+	// Skip the input if it has been built before.
+	if c.Cache != nil {
+		// Pages have to be built again when their backlinks or
+		// neighbours change.
+		c.InputHash = frieren.Hash(c.Input)
+		if c.Graph != nil {
+			location := yunyun.RelativePathTrim(c.Conf.Runtime.WorkDir.Rel(c.InputFilename))
+			c.InputHash = frieren.Hash(c.Input + c.Graph.Fingerprint(location))
+		}
+		if diagnostics, ok := c.Cache.Unchanged(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), c.InputHash); ok {
+			// The warnings are still there, so report them again.
+			misaka.RecordDiagnostics(diagnostics...)
+			return nil, ErrUnchanged
+		}
+	}
 	return c, nil
 }
 
 // Parse parses the input file and returns the Control.
-func (c *Control) Parse() Woof {
+func (c *Control) Parse() (Woof, error) {
 	defer puck.
 		Stopwatch("Parsed", "input", c.InputFilename).
 		RecordWithFile(misaka.RecordParseTime, c.InputFilename)
-	c.Page = c.Parser.Do(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), c.Input)
-	return c
+	page, err := c.Parser.Do(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), c.Input)
+	// Warnings are only recorded, the page is still good.
+	diagnostics := yunyun.Diagnostics{}
+	if err != nil && (!errors.As(err, &diagnostics) || diagnostics.HasErrors()) {
+		return nil, c.fail(err)
+	}
+	c.warn(diagnostics...)
+	if page == nil {
+		return nil, c.fail(fmt.Errorf("parsing %s produced no page", c.InputFilename))
+	}
+	c.Page = page
+	return c, nil
 }
 
 // Export exports the parsed page and returns the Control.
@@ -65,7 +106,18 @@ func (c *Control) Export() Woof {
 	defer puck.
 		Stopwatch("Exported", "input", c.InputFilename).
 		RecordWithFile(misaka.RecordExportTime, c.InputFilename)
-	c.OutputFilename = c.Conf.Project.InputFilenameToOutput(c.InputFilename)
+	c.OutputFilename = c.Conf.InputFilenameToOutput(c.InputFilename)
+	for _, item := range rem.MissingGalleryImages(c.Conf, c.Page) {
+		diagnostics := yunyun.Diagnostics{}
+		diagnostics.Warn(c.Page.File, "missing gallery image %s", yunyun.JoinRelativePaths(item.Path, item.Item))
+		c.warn(diagnostics...)
+	}
+	if c.Conf.Website.Backlinks {
+		c.Page.Backlinks = c.Graph.Backlinks(c.Page.Location)
+	}
+	if c.Conf.Website.Navigation {
+		c.Page.Navigation = c.Graph.Navigation(c.Page.Location)
+	}
 	c.Output = c.Exporter.Do(chiho.EnrichPage(c.Conf, c.Page))
 	return c
 }
@@ -75,12 +127,40 @@ func (c *Control) Write() error {
 	defer puck.
 		Stopwatch("Wrote", "output", c.OutputFilename).
 		RecordWithFile(misaka.RecordWriteTime, c.InputFilename)
+	// The output directory might not have this directory yet.
+	if err := os.MkdirAll(filepath.Dir(c.OutputFilename), 0o750); err != nil {
+		return c.fail(fmt.Errorf("creating output directory for %s: %v", c.OutputFilename, err))
+	}
+	// Remove the old output, in case it's a hard link to a published file.
+	if err := os.Remove(c.OutputFilename); err != nil && !os.IsNotExist(err) {
+		return c.fail(fmt.Errorf("removing old output file %s: %v", c.OutputFilename, err))
+	}
 	file, err := os.Create(c.OutputFilename)
 	if err != nil {
-		return fmt.Errorf("creating output file %s: %v", c.OutputFilename, err)
+		return c.fail(fmt.Errorf("creating output file %s: %v", c.OutputFilename, err))
 	}
 	if _, err := io.Copy(file, c.Output); err != nil {
-		return fmt.Errorf("writing to output file %s: %v", c.OutputFilename, err)
+		file.Close()
+		return c.fail(fmt.Errorf("writing to output file %s: %v", c.OutputFilename, err))
+	}
+	// The output is only written once it's flushed and closed.
+	if err := file.Close(); err != nil {
+		return c.fail(fmt.Errorf("closing output file %s: %v", c.OutputFilename, err))
+	}
+	if c.Cache != nil {
+		c.Cache.Store(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), c.InputHash, c.OutputFilename, c.Diagnostics)
 	}
 	return nil
 }
+
+// warn records the warnings of the input, which are remembered with it.
+func (c *Control) warn(diagnostics ...yunyun.Diagnostic) {
+	c.Diagnostics = append(c.Diagnostics, diagnostics...)
+	misaka.RecordDiagnostics(diagnostics...)
+}
+
+// fail records the error as a failure of the input and returns it.
+func (c *Control) fail(err error) error {
+	misaka.RecordFailure(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), err)
+	return err
+}
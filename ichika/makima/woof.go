@@ -4,7 +4,7 @@ package makima
 type Woof interface {
 	Read() (Woof, error)
 	// Parse parses the input internally.
-	Parse() Woof
+	Parse() (Woof, error)
 	// Export exports the result internally.
 	Export() Woof
 	// Write flushes the exported data.
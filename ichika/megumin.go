@@ -49,9 +49,20 @@ func CleanCommandFunc() {
 
 // removeOutputFiles is the low-level command to be used when cleaning data.
 func removeOutputFiles(conf *alpha.DarknessConfig) {
+	// The whole site lives in the output directory, so just blow it up.
+	if conf.HasOutputDirectory() {
+		toPrint := conf.Project.OutputDirectory
+		if err := os.RemoveAll(string(conf.Runtime.OutputDir)); err != nil {
+			fmt.Println(toPrint, "failed to blow up!!")
+		}
+		if !isQuietMegumin {
+			fmt.Println(toPrint, "went boom!")
+		}
+		return
+	}
 	inputFilenames := hizuru.FindFilesByExtSimple(conf)
 	for _, inputFilename := range inputFilenames {
-		toRemove := conf.Project.InputFilenameToOutput(inputFilename)
+		toRemove := conf.InputFilenameToOutput(inputFilename)
 		toPrint := conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(toRemove))
 		if err := os.Remove(toRemove); err != nil && !os.IsNotExist(err) {
 			fmt.Println(toPrint, "failed to blow up!!")
@@ -9,6 +9,7 @@ import (
 	"github.com/thecsw/darkness/emilia/alpha/roxy"
 	"github.com/thecsw/darkness/emilia/puck"
 	"github.com/thecsw/darkness/ichika/misa"
+	"github.com/thecsw/darkness/yunyun"
 )
 
 // MisaCommandFunc will support many different tools that darkness can support,
@@ -20,7 +21,16 @@ func MisaCommandFunc() {
 	removeGalleryPreviews := misaCmd.Bool("no-gallery-previews", false, "delete gallery previews")
 	addHolosceneTitles := misaCmd.Bool("holoscene-titles", false, "add holoscene titles")
 	rss := misaCmd.String("rss", "", "generate an rss file")
-	rssDirectories := misaCmd.String("rss-dirs", "", "look up specific dirs")
+	atom := misaCmd.String("atom", "", "generate an atom file")
+	jsonFeed := misaCmd.String("jsonfeed", "", "generate a json feed file")
+	rssDirectories := misaCmd.String("rss-dirs", "", "look up specific dirs (rss, atom, and json feed)")
+	sitemap := misaCmd.String("sitemap", "", "generate a sitemap file")
+	feeds := misaCmd.Bool("feeds", false, "generate all the feeds from the config")
+	tags := misaCmd.Bool("tags", false, "generate the tag pages")
+	indexes := misaCmd.Bool("indexes", false, "generate the missing directory indexes")
+	archive := misaCmd.Bool("archive", false, "generate the archive page")
+	searchIndex := misaCmd.Bool("search", false, "generate the search index")
+	linkGraph := misaCmd.String("link-graph", "", "generate a json file of the links between pages")
 	dryRun := misaCmd.Bool("dry-run", false, "skip writing files (but do the reading)")
 	pluginName := ""
 	misaCmd.StringVar(&pluginName, "plugin", "", "execute a misa plugin")
@@ -30,7 +40,7 @@ func MisaCommandFunc() {
 
 	puck.Logger.SetPrefix("Misa 🍎 ")
 
-	if len(*rss) > 0 {
+	if len(*rss) > 0 || len(*atom) > 0 || len(*jsonFeed) > 0 || len(*sitemap) > 0 || *feeds || *tags || *indexes || *archive || len(*linkGraph) > 0 || *searchIndex {
 		options.Dev = false
 	}
 	conf := alpha.BuildConfig(options)
@@ -47,8 +57,58 @@ func MisaCommandFunc() {
 		misa.UpdateHoloceneTitles(conf, *dryRun)
 		os.Exit(0)
 	}
-	if len(*rss) > 0 {
-		misa.GenerateRssFeed(conf, *rss, strings.Split(*rssDirectories, ","), *dryRun)
+	if len(*rss) > 0 || len(*atom) > 0 || len(*jsonFeed) > 0 {
+		if len(*rss) > 0 {
+			misa.GenerateRssFeed(conf, *rss, strings.Split(*rssDirectories, ","), *dryRun)
+		}
+		if len(*atom) > 0 {
+			misa.GenerateAtomFeed(conf, *atom, strings.Split(*rssDirectories, ","), *dryRun)
+		}
+		if len(*jsonFeed) > 0 {
+			misa.GenerateJsonFeed(conf, *jsonFeed, strings.Split(*rssDirectories, ","), *dryRun)
+		}
+		os.Exit(0)
+	}
+	if *feeds {
+		if err := misa.GenerateFeeds(conf, *dryRun); err != nil {
+			puck.Logger.Fatalf("generating feeds: %v", err)
+		}
+		os.Exit(0)
+	}
+	if *tags {
+		if err := misa.GenerateTagPages(conf, *dryRun); err != nil {
+			puck.Logger.Fatalf("generating tag pages: %v", err)
+		}
+		os.Exit(0)
+	}
+	if *indexes {
+		if err := misa.GenerateDirectoryIndexes(conf, *dryRun); err != nil {
+			puck.Logger.Fatalf("generating directory indexes: %v", err)
+		}
+		os.Exit(0)
+	}
+	if *archive {
+		if err := misa.GenerateArchive(conf, *dryRun); err != nil {
+			puck.Logger.Fatalf("generating archive: %v", err)
+		}
+		os.Exit(0)
+	}
+	if *searchIndex {
+		if err := misa.GenerateSearchIndex(conf, *dryRun); err != nil {
+			puck.Logger.Fatalf("generating search index: %v", err)
+		}
+		os.Exit(0)
+	}
+	if len(*linkGraph) > 0 {
+		if err := misa.GenerateLinkGraph(conf, yunyun.RelativePathFile(*linkGraph), *dryRun); err != nil {
+			puck.Logger.Fatalf("generating link graph: %v", err)
+		}
+		os.Exit(0)
+	}
+	if len(*sitemap) > 0 {
+		if err := misa.GenerateSitemap(conf, yunyun.RelativePathFile(*sitemap), *dryRun); err != nil {
+			puck.Logger.Fatalf("generating sitemap: %v", err)
+		}
 		os.Exit(0)
 	}
 	if pluginName != "" {
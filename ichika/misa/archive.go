@@ -0,0 +1,60 @@
+package misa
+
+import (
+	"fmt"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// GenerateArchive generates the archive page, which lists all the dated
+// pages from the newest to the oldest, grouped by years and months.
+func GenerateArchive(conf *alpha.DarknessConfig, dryRun bool) error {
+	if len(conf.Website.Archive) < 1 {
+		return fmt.Errorf("no archive directory is set in the config")
+	}
+	return writeArchive(conf, hizuru.BuildPagesSimple(conf, nil), dryRun)
+}
+
+// writeArchive writes the archive page of the given pages.
+func writeArchive(conf *alpha.DarknessConfig, pages []*yunyun.Page, dryRun bool) error {
+	dated := gana.Filter(func(page *yunyun.Page) bool {
+		_, dateFound := narumi.ConvertHoloscene(page.Date)
+		return dateFound && !page.Accoutrement.Draft.IsEnabled()
+	}, pages)
+	sortByDate(dated)
+
+	contents := make(yunyun.Contents, 0, len(dated))
+	lastYear, lastMonth := -1, -1
+	for _, page := range dated {
+		date, _ := narumi.ConvertHoloscene(page.Date)
+		if date.Year() != lastYear {
+			lastYear, lastMonth = date.Year(), -1
+			contents = append(contents, &yunyun.Content{
+				Type:         yunyun.TypeHeading,
+				Heading:      fmt.Sprintf("%d H.E.", date.Year()+10000),
+				HeadingLevel: 2,
+			})
+		}
+		if int(date.Month()) != lastMonth {
+			lastMonth = int(date.Month())
+			contents = append(contents,
+				&yunyun.Content{
+					Type:         yunyun.TypeHeading,
+					Heading:      date.Month().String(),
+					HeadingLevel: 3,
+				},
+				&yunyun.Content{Type: yunyun.TypeList},
+			)
+		}
+		list := contents[len(contents)-1]
+		list.List = append(list.List, pageListItem(conf, page))
+	}
+	if len(contents) < 1 {
+		contents = yunyun.Contents{{Type: yunyun.TypeParagraph, Paragraph: "Nothing has been published yet."}}
+	}
+	return writePage(conf, newGeneratedPage(conf.Website.Archive, "Archive", contents), dryRun)
+}
@@ -0,0 +1,76 @@
+package misa
+
+import (
+	"os"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/atom"
+)
+
+// GenerateAtomFeed generates an atom feed based on the given config and directories.
+func GenerateAtomFeed(conf *alpha.DarknessConfig, atomFilename string, atomDirectories []string, dryRun bool) {
+	pages := hizuru.BuildPagesSimple(conf, atomDirectories)
+	selected := selectFeed(conf, pages, pages, 0)
+	if err := writeAtomFeed(conf, yunyun.RelativePathFile(atomFilename), conf.Title, selected, dryRun); err != nil {
+		logger.Error("Writing the atom feed", "err", err)
+		os.Exit(1)
+	}
+}
+
+// writeAtomFeed writes the selected pages as an atom feed with the given title.
+func writeAtomFeed(conf *alpha.DarknessConfig, atomFilename yunyun.RelativePathFile, title string, selected *feed, dryRun bool) error {
+	// Create atom entries.
+	entries := make([]atom.Entry, 0, len(selected.entries))
+	for _, entry := range selected.entries {
+		entries = append(entries, atom.Entry{
+			ID:         entry.link,
+			Title:      &atom.Text{Type: "text", Value: entry.title},
+			Updated:    entry.date.Format(atom.AtomFormat),
+			Published:  entry.date.Format(atom.AtomFormat),
+			Links:      []atom.Link{{Href: entry.link, Rel: "alternate", Type: "text/html"}},
+			Author:     atomPerson(entry.page.Author),
+			Categories: []atom.Category{{Term: entry.categoryName, Scheme: entry.categoryLink}},
+			Summary:    &atom.Text{Type: "text", Value: entry.description},
+		})
+		if len(entry.content) > 0 {
+			entries[len(entries)-1].Content = &atom.Text{Type: "html", Value: entry.content}
+		}
+		if entry.enclosure != nil {
+			entries[len(entries)-1].Links = append(entries[len(entries)-1].Links, atom.Link{
+				Href:   entry.enclosure.url,
+				Rel:    "enclosure",
+				Type:   entry.enclosure.mimeType,
+				Length: entry.enclosure.length,
+			})
+		}
+	}
+
+	// Create the final feed.
+	feed := &atom.Feed{
+		Namespace: atom.Namespace,
+		ID:        conf.Url,
+		Title:     &atom.Text{Type: "text", Value: yunyun.FancyText(title)},
+		Subtitle:  &atom.Text{Type: "text", Value: yunyun.FancyText(selected.description)},
+		Updated:   selected.pubDate.Format(atom.AtomFormat),
+		Links: []atom.Link{
+			{Href: conf.Url, Rel: "alternate", Type: "text/html"},
+			{Href: conf.Url + string(atomFilename), Rel: "self", Type: "application/atom+xml"},
+		},
+		Author:    atomPerson(conf.RSS.DefaultAuthor),
+		Rights:    conf.RSS.Copyright,
+		Generator: feedGenerator,
+		Entries:   entries,
+	}
+
+	return writeXml(conf, atomFilename, feed, dryRun)
+}
+
+// atomPerson returns the author, nil if there is none.
+func atomPerson(name string) *atom.Person {
+	if len(name) < 1 {
+		return nil
+	}
+	return &atom.Person{Name: name}
+}
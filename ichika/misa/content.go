@@ -0,0 +1,49 @@
+package misa
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/ichika/chiho"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// linkAttributeRegexp matches the html attributes that hold links.
+var linkAttributeRegexp = regexp.MustCompile(`\b(href|src|data-src|poster)="([^"]*)"`)
+
+// contentCache remembers the exported pages, as enriching a page
+// again would add its scripts and alike twice.
+var contentCache = make(map[*yunyun.Page]string)
+
+// fullContent returns the exported page's body for the feed readers,
+// where all the relative links are made absolute.
+func fullContent(conf *alpha.DarknessConfig, page *yunyun.Page) string {
+	if v, ok := contentCache[page]; ok {
+		return v
+	}
+	body := html.ExporterHtml{Config: conf}.Body(chiho.EnrichPage(conf, page))
+	// The root page's url already ends with a slash.
+	base, err := url.Parse(strings.TrimSuffix(pageUrl(conf, page), "/") + "/")
+	if err != nil {
+		logger.Warn("Parsing the page's url", "page", page.File, "err", err)
+	} else {
+		body = absoluteLinks(body, base)
+	}
+	contentCache[page] = body
+	return body
+}
+
+// absoluteLinks resolves the relative links of the html against the base.
+func absoluteLinks(body string, base *url.URL) string {
+	return linkAttributeRegexp.ReplaceAllStringFunc(body, func(what string) string {
+		matches := linkAttributeRegexp.FindStringSubmatch(what)
+		link, err := url.Parse(matches[2])
+		if err != nil || link.IsAbs() || len(link.Host) > 0 {
+			return what
+		}
+		return matches[1] + `="` + base.ResolveReference(link).String() + `"`
+	})
+}
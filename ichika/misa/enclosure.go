@@ -0,0 +1,84 @@
+package misa
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// mediaTypes are the MIME types of the media that pages can enclose.
+var mediaTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".flac": "audio/flac",
+	".midi": "audio/midi",
+	".ogg":  "audio/ogg",
+	".wav":  "audio/wav",
+	".mp4":  "video/mp4",
+	".mkv":  "video/x-matroska",
+	".mov":  "video/quicktime",
+	".flv":  "video/x-flv",
+	".webm": "video/webm",
+}
+
+// feedEnclosure is the media file attached to a feed entry.
+type feedEnclosure struct {
+	// url is the full url of the file.
+	url string
+	// mimeType is the type of the file.
+	mimeType string
+	// length is the size of the file in bytes, zero if it's unknown.
+	length int64
+	// duration is the duration in seconds given by the page, optional.
+	duration string
+}
+
+// findEnclosure returns the media file of the page, which is either given
+// by the page's options or its first audio or video link, nil if none.
+func findEnclosure(conf *alpha.DarknessConfig, page *yunyun.Page) *feedEnclosure {
+	link := strings.TrimSpace(page.Accoutrement.Enclosure)
+	if len(link) < 1 {
+		for _, content := range page.Contents {
+			cleanLink := strings.TrimSpace(content.Link)
+			if content.IsLink() && (yunyun.AudioFileExtRegexp.MatchString(cleanLink) ||
+				yunyun.VideoFileExtRegexp.MatchString(cleanLink)) {
+				link = cleanLink
+				break
+			}
+		}
+	}
+	if len(link) < 1 {
+		return nil
+	}
+
+	result := &feedEnclosure{
+		url:      link,
+		mimeType: mediaTypes[strings.ToLower(filepath.Ext(link))],
+		duration: page.Accoutrement.EnclosureDuration,
+	}
+	if len(result.mimeType) < 1 {
+		logger.Warn("Unknown enclosure type", "page", page.File, "enclosure", link)
+		result.mimeType = "application/octet-stream"
+	}
+	// Remote files are left with an unknown length.
+	if yunyun.UrlRegexp.MatchString(link) {
+		return result
+	}
+
+	// Local files are relative to the page, unless they start at the root.
+	filename := yunyun.JoinRelativePaths(page.Location, yunyun.RelativePathFile(link))
+	if strings.HasPrefix(link, "/") {
+		filename = yunyun.RelativePathFile(strings.TrimPrefix(link, "/"))
+	}
+	result.url = conf.Url + filepath.ToSlash(string(filename))
+	info, err := os.Stat(string(conf.Runtime.WorkDir.Join(filename)))
+	if err != nil {
+		logger.Warn("Finding the enclosure", "page", page.File, "err", err)
+		return result
+	}
+	result.length = info.Size()
+	return result
+}
@@ -0,0 +1,159 @@
+package misa
+
+import (
+	"sort"
+	"time"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+const (
+	// feedGenerator is the generator string used in the feeds.
+	feedGenerator = "Darkness (sandyuraz.com/darkness)"
+)
+
+// feed is the website's pages selected for the feeds, which
+// is the same for rss, atom, and json feeds.
+type feed struct {
+	// description is the description of the whole website.
+	description string
+	// pubDate is the date of the latest entry, build date if none.
+	pubDate time.Time
+	// buildDate is the time the feed was built.
+	buildDate time.Time
+	// entries are the published pages in descending order of dates.
+	entries []feedEntry
+}
+
+// feedEntry is a single page in the feed.
+type feedEntry struct {
+	// page is the page itself.
+	page *yunyun.Page
+	// title is the page's title with the rss accoutrements.
+	title string
+	// link is the full url of the page.
+	link string
+	// description is the summary of the page.
+	description string
+	// content is the whole exported page, empty unless enabled.
+	content string
+	// enclosure is the page's media file, nil if none.
+	enclosure *feedEnclosure
+	// categoryName is the title of the page's parent.
+	categoryName string
+	// categoryLink is the full url of the page's parent.
+	categoryLink string
+	// date is the publication date of the page.
+	date time.Time
+}
+
+// selectFeed builds the feed out of the pages with dates, where drafts are
+// skipped and only the newest limit entries are kept, unless it's zero. All
+// the pages are used to find the website's description and categories.
+func selectFeed(conf *alpha.DarknessConfig, allPages, pages []*yunyun.Page, limit int) *feed {
+	// Try to retrieve the top root page to get channel description. If not found, use the
+	// website's title as the description.
+	topPage := gana.First(gana.Filter(func(page *yunyun.Page) bool { return page.Location == "." }, allPages))
+	rootDescription := conf.RSS.Description
+	if topPage != nil {
+		rootDescription = getDescription(topPage, conf.Website.DescriptionLength*4)
+	}
+	// If both the top page and RSS config have no description, default to the title.
+	if len(rootDescription) < 1 {
+		rootDescription = conf.Title
+	}
+
+	// Get all pages that have dates defined, we only use those to be included in the feeds.
+	datedPages := Pages(gana.Filter(func(page *yunyun.Page) bool {
+		_, dateFound := narumi.ConvertHoloscene(page.Date)
+		return dateFound
+	}, pages))
+
+	// Sort the pages in descending order of dates, where the titles break ties.
+	sort.Slice(datedPages, func(i, j int) bool { return datedPages[i].Title < datedPages[j].Title })
+	sort.Stable(datedPages)
+
+	// Try to find the pub date, if none, then reuse the build date
+	result := &feed{
+		description: rootDescription,
+		buildDate:   time.Now(),
+		entries:     make([]feedEntry, 0, len(pages)),
+	}
+	result.pubDate = result.buildDate
+	if firstPage := gana.First(datedPages); firstPage != nil {
+		result.pubDate = mustDate(firstPage)
+	}
+
+	defer puck.Stopwatch("Built feed pages", "num", len(datedPages)).Record()
+	for _, page := range datedPages {
+		// Skip drafts.
+		if page.Accoutrement.Draft.IsEnabled() {
+			continue
+		}
+		// Stop when the feed is full.
+		if limit > 0 && len(result.entries) >= limit {
+			break
+		}
+		// Create the category name and location.
+		categoryName, categoryLocation := page.Title, page.Location
+		if categoryPage := getCategory(page, allPages); categoryPage != nil {
+			categoryName = categoryPage.Title
+			categoryLocation = categoryPage.Location
+		}
+
+		// Override the title if the page has a custom RSS title.
+		finalTitle := page.Title
+		if len(page.Accoutrement.RssTitle) > 0 {
+			finalTitle = page.Accoutrement.RssTitle
+		}
+
+		// Add the RSS prefix to the title.
+		finalTitle = page.Accoutrement.RssPrefix + " " + finalTitle
+
+		content := ""
+		if conf.RSS.FullContent {
+			content = fullContent(conf, page)
+		}
+
+		result.entries = append(result.entries, feedEntry{
+			page:         page,
+			title:        yunyun.RemoveFormatting(yunyun.FancyText(finalTitle)),
+			link:         conf.Url + string(page.Location),
+			description:  yunyun.FancyText(getDescription(page, conf.Website.DescriptionLength*4)),
+			content:      content,
+			enclosure:    findEnclosure(conf, page),
+			categoryName: categoryName,
+			categoryLink: conf.Url + string(categoryLocation),
+			date:         feedDate(conf, page),
+		})
+	}
+	return result
+}
+
+// feedDate returns the page's date in the feeds' timezone, where the
+// default hour is used if the page has no time.
+func feedDate(conf *alpha.DarknessConfig, page *yunyun.Page) time.Time {
+	parsedDate, _ := narumi.ConvertHoloscene(page.Date)
+	finalLocation, err := time.LoadLocation(conf.RSS.Timezone)
+	// Fallback to UTC
+	if err != nil {
+		finalLocation = time.UTC
+	}
+	hour, minute := parsedDate.Hour(), parsedDate.Minute()
+	if hour == 0 && minute == 0 {
+		hour = conf.RSS.DefaultHour
+		minute = 0
+	}
+	return time.Date(
+		parsedDate.Year(), parsedDate.Month(), parsedDate.Day(),
+		hour, minute, 0, 0, finalLocation)
+}
+
+// hasEnclosures tells us if any of the entries have media files.
+func (f *feed) hasEnclosures() bool {
+	return gana.Anyf(func(entry feedEntry) bool { return entry.enclosure != nil }, f.entries)
+}
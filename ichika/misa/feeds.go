@@ -0,0 +1,69 @@
+package misa
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// GenerateFeeds generates all the feeds from the config's feeds section,
+// where the whole website is only parsed once for all of them.
+func GenerateFeeds(conf *alpha.DarknessConfig, dryRun bool) error {
+	if len(conf.Feeds) < 1 {
+		return errors.New("no feeds are defined in the config")
+	}
+	allPages := hizuru.BuildPagesSimple(conf, nil)
+	for _, feedConf := range conf.Feeds {
+		pages := gana.Filter(func(page *yunyun.Page) bool { return includedInFeed(feedConf, page) }, allPages)
+		selected := selectFeed(conf, allPages, pages, feedConf.Limit)
+
+		title := feedConf.Title
+		if len(title) < 1 {
+			title = conf.Title
+		}
+
+		var err error
+		switch feedConf.Format {
+		case alpha.FeedFormatAtom:
+			err = writeAtomFeed(conf, feedConf.Output, title, selected, dryRun)
+		case alpha.FeedFormatJson:
+			err = writeJsonFeed(conf, feedConf.Output, title, selected, dryRun)
+		default:
+			err = writeRssFeed(conf, feedConf.Output, title, selected, dryRun)
+		}
+		if err != nil {
+			return fmt.Errorf("writing feed %s: %v", feedConf.Output, err)
+		}
+	}
+	return nil
+}
+
+// includedInFeed tells us if the page belongs in the feed by its
+// directories and tags, where an empty filter lets everything in.
+func includedInFeed(feedConf alpha.FeedConfig, page *yunyun.Page) bool {
+	if len(feedConf.Directories) > 0 && !gana.Anyf(func(directory string) bool {
+		return inDirectory(string(page.File), directory)
+	}, feedConf.Directories) {
+		return false
+	}
+	if len(feedConf.Tags) > 0 && !gana.Anyf(page.HasTag, feedConf.Tags) {
+		return false
+	}
+	return true
+}
+
+// inDirectory tells us if the file is under the directory, comparing
+// whole path components, so that `blog` doesn't take `blogroll/`.
+func inDirectory(file, directory string) bool {
+	directory = strings.TrimSuffix(path.Clean(directory), "/")
+	if directory == "." {
+		return true
+	}
+	return file == directory || strings.HasPrefix(file, directory+"/")
+}
@@ -0,0 +1,56 @@
+package misa
+
+import (
+	"fmt"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/ichika/kaguya"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// AfterBuild generates everything the config asks for after every build,
+// like the sitemap or the tag pages, from a single parse of the website.
+func AfterBuild(conf *alpha.DarknessConfig) error {
+	website := conf.Website
+	if len(website.Sitemap) < 1 && len(website.Tags) < 1 && !website.DirectoryIndexes && len(website.Archive) < 1 && len(website.LinkGraph) < 1 && !website.Search {
+		return nil
+	}
+	pages := hizuru.BuildPagesSimple(conf, nil)
+	if len(website.Sitemap) > 0 {
+		if err := writeSitemap(conf, pages, website.Sitemap, false); err != nil {
+			return fmt.Errorf("generating the sitemap: %v", err)
+		}
+	}
+	if len(website.Tags) > 0 {
+		if err := writeTagPages(conf, pages, false); err != nil {
+			return fmt.Errorf("generating the tag pages: %v", err)
+		}
+	}
+	if website.DirectoryIndexes {
+		if err := writeDirectoryIndexes(conf, pages, false); err != nil {
+			return fmt.Errorf("generating the directory indexes: %v", err)
+		}
+	}
+	if len(website.Archive) > 0 {
+		if err := writeArchive(conf, pages, false); err != nil {
+			return fmt.Errorf("generating the archive: %v", err)
+		}
+	}
+	if len(website.LinkGraph) > 0 {
+		if err := writeJson(conf, website.LinkGraph, kaguya.BuildGraph(conf, pages), false); err != nil {
+			return fmt.Errorf("generating the link graph: %v", err)
+		}
+	}
+	if website.Search {
+		if err := writeSearchIndex(conf, pages, false); err != nil {
+			return fmt.Errorf("generating the search index: %v", err)
+		}
+	}
+	return nil
+}
+
+// GenerateLinkGraph generates the json file of the links between the pages.
+func GenerateLinkGraph(conf *alpha.DarknessConfig, filename yunyun.RelativePathFile, dryRun bool) error {
+	return writeJson(conf, filename, kaguya.BuildGraph(conf, hizuru.BuildPagesSimple(conf, nil)), dryRun)
+}
@@ -34,7 +34,7 @@ func UpdateHoloceneTitles(conf *alpha.DarknessConfig, dryRun bool) {
 	// Convert the input filenames to output filenames.
 	outputs := make([]string, len(inputFilenames))
 	for i, inputFilename := range inputFilenames {
-		outputs[i] = conf.Project.InputFilenameToOutput(inputFilename)
+		outputs[i] = conf.InputFilenameToOutput(inputFilename)
 	}
 
 	// Open all the output files.
@@ -0,0 +1,94 @@
+package misa
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// GenerateDirectoryIndexes generates a listing page for every directory
+// that has pages under it, but no page of its own.
+func GenerateDirectoryIndexes(conf *alpha.DarknessConfig, dryRun bool) error {
+	return writeDirectoryIndexes(conf, hizuru.BuildPagesSimple(conf, nil), dryRun)
+}
+
+// writeDirectoryIndexes writes the listing pages of the given pages' directories.
+func writeDirectoryIndexes(conf *alpha.DarknessConfig, pages []*yunyun.Page, dryRun bool) error {
+	sources := make(map[yunyun.RelativePathDir]*yunyun.Page, len(pages))
+	for _, page := range pages {
+		sources[page.Location] = page
+	}
+
+	// Every directory above a page without a page of its own gets a listing.
+	listed := make(map[yunyun.RelativePathDir]bool)
+	for _, page := range pages {
+		for directory := page.Location; directory != "."; {
+			directory = yunyun.RelativePathDir(filepath.Dir(string(directory)))
+			if _, ok := sources[directory]; !ok {
+				listed[directory] = true
+			}
+		}
+	}
+
+	// Both the pages and the listings are children of their parents.
+	children := make(map[yunyun.RelativePathDir][]yunyun.RelativePathDir)
+	for location := range sources {
+		children[parentDirectory(location)] = append(children[parentDirectory(location)], location)
+	}
+	for location := range listed {
+		children[parentDirectory(location)] = append(children[parentDirectory(location)], location)
+	}
+
+	for location := range listed {
+		childPages := make([]*yunyun.Page, 0, len(children[location]))
+		childDirectories := make([]yunyun.RelativePathDir, 0, len(children[location]))
+		for _, child := range children[location] {
+			if child == location {
+				continue
+			}
+			page, ok := sources[child]
+			if !ok {
+				childDirectories = append(childDirectories, child)
+				continue
+			}
+			// Drafts are not published.
+			if !page.Accoutrement.Draft.IsEnabled() {
+				childPages = append(childPages, page)
+			}
+		}
+		sortByDate(childPages)
+		sort.Slice(childDirectories, func(i, j int) bool { return childDirectories[i] < childDirectories[j] })
+
+		items := make([]yunyun.ListItem, 0, len(childPages)+len(childDirectories))
+		for _, page := range childPages {
+			items = append(items, pageListItem(conf, page))
+		}
+		for _, directory := range childDirectories {
+			items = append(items, yunyun.ListItem{Level: 1, Text: fmt.Sprintf("[[%s][%s/]]",
+				conf.Runtime.Join(yunyun.RelativePathFile(directory)), filepath.Base(string(directory)))})
+		}
+		if len(items) < 1 {
+			continue
+		}
+
+		title := conf.Title
+		if location != "." {
+			title = filepath.Base(string(location))
+		}
+		if err := writePage(conf, newGeneratedPage(location, title, yunyun.Contents{
+			{Type: yunyun.TypeList, List: items},
+		}), dryRun); err != nil {
+			return fmt.Errorf("writing directory index of %s: %v", location, err)
+		}
+	}
+	return nil
+}
+
+// parentDirectory returns the directory above, where the root is its own parent.
+func parentDirectory(location yunyun.RelativePathDir) yunyun.RelativePathDir {
+	return yunyun.RelativePathDir(filepath.Dir(string(location)))
+}
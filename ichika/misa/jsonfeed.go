@@ -0,0 +1,71 @@
+package misa
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/jsonfeed"
+)
+
+// GenerateJsonFeed generates a JSON feed based on the given config and directories.
+func GenerateJsonFeed(conf *alpha.DarknessConfig, jsonFilename string, jsonDirectories []string, dryRun bool) {
+	pages := hizuru.BuildPagesSimple(conf, jsonDirectories)
+	selected := selectFeed(conf, pages, pages, 0)
+	if err := writeJsonFeed(conf, yunyun.RelativePathFile(jsonFilename), conf.Title, selected, dryRun); err != nil {
+		logger.Error("Writing the json feed", "err", err)
+		os.Exit(1)
+	}
+}
+
+// writeJsonFeed writes the selected pages as a json feed with the given title.
+func writeJsonFeed(conf *alpha.DarknessConfig, jsonFilename yunyun.RelativePathFile, title string, selected *feed, dryRun bool) error {
+	// Create JSON feed items.
+	items := make([]jsonfeed.Item, 0, len(selected.entries))
+	for _, entry := range selected.entries {
+		items = append(items, jsonfeed.Item{
+			ID:            entry.link,
+			Url:           entry.link,
+			Title:         entry.title,
+			ContentHtml:   entry.content,
+			ContentText:   entry.description,
+			Summary:       entry.description,
+			DatePublished: entry.date.Format(jsonfeed.JsonFeedFormat),
+			Authors:       jsonFeedAuthors(entry.page.Author),
+			Tags:          []string{entry.categoryName},
+		})
+		if entry.enclosure != nil {
+			duration, _ := strconv.Atoi(entry.enclosure.duration)
+			items[len(items)-1].Attachments = []jsonfeed.Attachment{{
+				Url:               entry.enclosure.url,
+				MimeType:          entry.enclosure.mimeType,
+				SizeInBytes:       entry.enclosure.length,
+				DurationInSeconds: duration,
+			}}
+		}
+	}
+
+	// Create the final feed.
+	feed := &jsonfeed.Feed{
+		Version:     jsonfeed.Version,
+		Title:       yunyun.FancyText(title),
+		HomePageUrl: conf.Url,
+		FeedUrl:     conf.Url + string(jsonFilename),
+		Description: yunyun.FancyText(selected.description),
+		Language:    conf.RSS.Language,
+		Authors:     jsonFeedAuthors(conf.RSS.DefaultAuthor),
+		Items:       items,
+	}
+
+	return writeJson(conf, jsonFilename, feed, dryRun)
+}
+
+// jsonFeedAuthors returns the author as a list, nil if there is none.
+func jsonFeedAuthors(name string) []jsonfeed.Author {
+	if len(name) < 1 {
+		return nil
+	}
+	return []jsonfeed.Author{{Name: name}}
+}
@@ -0,0 +1,90 @@
+package misa
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// createOutputFile creates the file in the output directory, stdout on dry runs.
+func createOutputFile(conf *alpha.DarknessConfig, filename yunyun.RelativePathFile, dryRun bool) (io.WriteCloser, string, error) {
+	if dryRun {
+		return nopCloser{os.Stdout}, "stdout", nil
+	}
+	target := string(conf.Runtime.OutputDir.Join(filename))
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return nil, target, fmt.Errorf("creating directory %s: %v", target, err)
+	}
+	// Don't write through a hard link of a published file.
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return nil, target, fmt.Errorf("removing old file %s: %v", target, err)
+	}
+	file, err := os.Create(filepath.Clean(target))
+	if err != nil {
+		return nil, target, fmt.Errorf("creating file %s: %v", target, err)
+	}
+	return file, target, nil
+}
+
+// writeXml encodes the value into the file in the output directory,
+// stdout on dry runs.
+func writeXml(conf *alpha.DarknessConfig, filename yunyun.RelativePathFile, what any, dryRun bool) error {
+	file, target, err := createOutputFile(conf, filename, dryRun)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, xml.Header); err != nil {
+		return fmt.Errorf("writing to %s: %v", target, err)
+	}
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(what); err != nil {
+		return fmt.Errorf("encoding to xml %s: %v", target, err)
+	}
+	if _, err := io.WriteString(file, "\n"); err != nil {
+		return fmt.Errorf("writing to %s: %v", target, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing file %s: %v", target, err)
+	}
+	if !dryRun {
+		logger.Info("Created file", "path", filename)
+	}
+	return nil
+}
+
+// writeJson encodes the value into the file in the output directory,
+// stdout on dry runs.
+func writeJson(conf *alpha.DarknessConfig, filename yunyun.RelativePathFile, what any, dryRun bool) error {
+	file, target, err := createOutputFile(conf, filename, dryRun)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(what); err != nil {
+		return fmt.Errorf("encoding to json %s: %v", target, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing file %s: %v", target, err)
+	}
+	if !dryRun {
+		logger.Info("Created file", "path", filename)
+	}
+	return nil
+}
+
+// nopCloser doesn't close the writer, like stdout.
+type nopCloser struct {
+	io.Writer
+}
+
+// Close does nothing.
+func (nopCloser) Close() error { return nil }
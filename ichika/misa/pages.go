@@ -0,0 +1,159 @@
+package misa
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/kurisu"
+	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/export"
+	"github.com/thecsw/darkness/ichika/chiho"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// newGeneratedPage returns an empty page at the location, which isn't
+// backed by any source file.
+func newGeneratedPage(location yunyun.RelativePathDir, title string, contents yunyun.Contents) *yunyun.Page {
+	page := yunyun.NewPage(
+		yunyun.WithLocation(location),
+		yunyun.WithFilename(yunyun.JoinRelativePaths(location, "index")),
+		yunyun.WithContents(contents),
+	)
+	page.Title = title
+	page.Date = ""
+	page.DateHoloscene = false
+	return page
+}
+
+// writePage exports the generated page into its location's index file,
+// stdout on dry runs.
+func writePage(conf *alpha.DarknessConfig, page *yunyun.Page, dryRun bool) error {
+	filename := yunyun.JoinRelativePaths(page.Location, yunyun.RelativePathFile("index"+conf.Project.Output))
+	file, target, err := createOutputFile(conf, filename, dryRun)
+	if err != nil {
+		return err
+	}
+	output := export.BuildExporter(conf).Do(chiho.EnrichPage(conf, page))
+	if _, err := io.Copy(file, output); err != nil {
+		return fmt.Errorf("writing to %s: %v", target, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing file %s: %v", target, err)
+	}
+	if !dryRun {
+		logger.Info("Created file", "path", filename)
+	}
+	return nil
+}
+
+// pageListItem returns the list item that links to the page, followed
+// by its date and description.
+func pageListItem(conf *alpha.DarknessConfig, page *yunyun.Page) yunyun.ListItem {
+	text := fmt.Sprintf("[[%s][%s]]", conf.Runtime.Join(yunyun.RelativePathFile(page.Location)), page.Title)
+	if page.HasDate() {
+		text += ", " + page.Date
+	}
+	if description := getSummary(page, conf.Website.DescriptionLength); len(description) > 0 {
+		text += " — " + description
+	}
+	return yunyun.ListItem{Level: 1, Text: text}
+}
+
+// getSummary returns the description of the page for the lists, which
+// unlike `getDescription` keeps the verbatim text and the math whole, as
+// the list is exported again and would otherwise mangle them.
+func getSummary(page *yunyun.Page, length int) string {
+	for _, content := range page.Contents {
+		if !content.IsParagraph() {
+			continue
+		}
+		paragraph := strings.TrimSpace(content.Paragraph)
+		if paragraph == "" || puck.HEregex.MatchString(paragraph) {
+			continue
+		}
+		if summary := truncateSummary(removeFormattingOutsideVerbatim(paragraph), length); len(summary) >= descriptionMinLength {
+			return summary
+		}
+	}
+	return ""
+}
+
+// removeFormattingOutsideVerbatim removes the markup of the text but the
+// verbatim text, which keeps its markers so that it stays code.
+func removeFormattingOutsideVerbatim(text string) string {
+	result := strings.Builder{}
+	last := 0
+	for _, verbatim := range yunyun.VerbatimText.FindAllStringIndex(text, -1) {
+		result.WriteString(removeFormattingKeepSpaces(text[last:verbatim[0]]))
+		result.WriteString(text[verbatim[0]:verbatim[1]])
+		last = verbatim[1]
+	}
+	result.WriteString(removeFormattingKeepSpaces(text[last:]))
+	return strings.TrimSpace(result.String())
+}
+
+// removeFormattingKeepSpaces is `yunyun.RemoveFormatting` that keeps the
+// spaces around the text, which separate it from the verbatim text.
+func removeFormattingKeepSpaces(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := strings.Index(text, trimmed)
+	return text[:start] + yunyun.RemoveFormatting(trimmed) + text[start+len(trimmed):]
+}
+
+// truncateSummary cuts the text down to the length, without ever cutting
+// through the verbatim text or the math, and marks the cut with an ellipsis.
+func truncateSummary(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+	verbatim := yunyun.VerbatimText.FindAllStringIndex(text, -1)
+	whole := append([][]int{}, verbatim...)
+	for _, math := range kurisu.FindAllOutside(text, yunyun.MathlessRanges(text)) {
+		whole = append(whole, []int{math.Start, math.End})
+	}
+	cut := length
+	for !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	for _, span := range whole {
+		if span[0] < cut && cut < span[1] {
+			cut = span[0]
+		}
+	}
+	// The text starts with something too long to cut, so take all of it.
+	if strings.TrimSpace(text[:cut]) == "" {
+		for _, span := range whole {
+			if span[0] <= cut && cut < span[1] {
+				cut = span[1]
+			}
+		}
+	}
+	if cut >= len(text) {
+		return text
+	}
+	return strings.TrimSpace(text[:cut]) + "..."
+}
+
+// sortByDate sorts the pages from the newest to the oldest, where the
+// pages with no dates go last, and the titles break ties.
+func sortByDate(pages []*yunyun.Page) {
+	sort.SliceStable(pages, func(i, j int) bool {
+		left, leftFound := narumi.ConvertHoloscene(pages[i].Date)
+		right, rightFound := narumi.ConvertHoloscene(pages[j].Date)
+		if leftFound != rightFound {
+			return leftFound
+		}
+		if !left.Equal(right) {
+			return left.After(right)
+		}
+		return pages[i].Title < pages[j].Title
+	})
+}
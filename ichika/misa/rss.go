@@ -4,7 +4,7 @@ import (
 	"encoding/xml"
 	"os"
 	"path/filepath"
-	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,104 +17,48 @@ import (
 	"github.com/thecsw/gana"
 )
 
-const (
-	// rssGenerator is the generator string used in the RSS feed.
-	rssGenerator = "Darkness (sandyuraz.com/darkness)"
-)
-
 // GenerateRssFeed generates an RSS feed based on the given config and directories.
 func GenerateRssFeed(conf *alpha.DarknessConfig, rssFilename string, rssDirectories []string, dryRun bool) {
-	// Get all all the pages we can build out.
-	allPages := hizuru.BuildPagesSimple(conf, rssDirectories)
-	// Try to retrieve the top root page to get channel description. If not found, use the
-	// website's title as the description.
-	topPage := gana.First(gana.Filter(func(page *yunyun.Page) bool { return page.Location == "." }, allPages))
-	rootDescription := conf.RSS.Description
-	if topPage != nil {
-		rootDescription = getDescription(topPage, conf.Website.DescriptionLength*4)
-	}
-	// If both the top page and RSS config have no description, default to the title.
-	if len(rootDescription) < 1 {
-		rootDescription = conf.Title
+	pages := hizuru.BuildPagesSimple(conf, rssDirectories)
+	selected := selectFeed(conf, pages, pages, 0)
+	if err := writeRssFeed(conf, yunyun.RelativePathFile(rssFilename), conf.Title, selected, dryRun); err != nil {
+		logger.Error("Writing the rss feed", "err", err)
+		os.Exit(1)
 	}
+}
 
-	sort.Slice(allPages, func(i, j int) bool { return allPages[i].Title < allPages[j].Title })
-
-	// Get all pages that have dates defined, we only use those to be included in the rss feed.
-	pages := Pages(gana.Filter(func(page *yunyun.Page) bool {
-		_, dateFound := narumi.ConvertHoloscene(page.Date)
-		return dateFound
-	}, allPages))
-
-	// Sort the pages in descending order of dates.
-	sort.Sort(pages)
-
+// writeRssFeed writes the selected pages as an rss feed with the given title.
+func writeRssFeed(conf *alpha.DarknessConfig, rssFilename yunyun.RelativePathFile, title string, selected *feed, dryRun bool) error {
 	// Create RSS items.
-	items := make([]rss.Item, 0, len(pages))
-
-	func() {
-		defer puck.Stopwatch("Built RSS pages", "num", len(pages)).Record()
-		for _, page := range pages {
-			// Skip drafts.
-			if page.Accoutrement.Draft.IsEnabled() {
-				continue
-			}
-			// Create the category name and location.
-			categoryName, categoryLocation := page.Title, page.Location
-			if categoryPage := getCategory(page, allPages); categoryPage != nil {
-				categoryName = categoryPage.Title
-				categoryLocation = categoryPage.Location
-			}
-
-			// Override the title if the page has a custom RSS title.
-			finalTitle := page.Title
-			if len(page.Accoutrement.RssTitle) > 0 {
-				finalTitle = page.Accoutrement.RssTitle
-			}
-
-			// Add the RSS prefix to the title.
-			finalTitle = page.Accoutrement.RssPrefix + " " + finalTitle
-
-			// Let's update the time if needed.
-			parsedDate, _ := narumi.ConvertHoloscene(page.Date)
-			finalLocation, err := time.LoadLocation(conf.RSS.Timezone)
-			// Fallback to UTC
-			if err != nil {
-				finalLocation = time.UTC
+	items := make([]rss.Item, 0, len(selected.entries))
+	for _, entry := range selected.entries {
+		items = append(items, rss.Item{
+			XMLName:     xml.Name{},
+			Title:       entry.title,
+			Link:        entry.link,
+			Description: entry.description + " [ Continue reading... ]",
+			Author:      entry.page.Author,
+			Category:    &rss.Category{Value: entry.categoryName, Domain: entry.categoryLink},
+			Guid:        &rss.Guid{Value: entry.link, IsPermaLink: true},
+			PubDate:     entry.date.Format(rss.RSSFormat),
+			Source:      &rss.Source{Value: conf.Title, Url: conf.Url},
+		})
+		if len(entry.content) > 0 {
+			items[len(items)-1].ContentEncoded = &rss.ContentEncoded{Value: entry.content}
+		}
+		if entry.enclosure != nil {
+			item := &items[len(items)-1]
+			item.Enclosure = &rss.Enclosure{
+				Url:    entry.enclosure.url,
+				Type:   entry.enclosure.mimeType,
+				Length: entry.enclosure.length,
 			}
-			hour, minute := parsedDate.Hour(), parsedDate.Minute()
-			if hour == 0 && minute == 0 {
-				hour = conf.RSS.DefaultHour
-				minute = 0
+			item.ITunesDuration = entry.enclosure.duration
+			item.ITunesEpisode = entry.page.Accoutrement.Episode
+			if !entry.page.Accoutrement.Explicit.IsDefault() {
+				item.ITunesExplicit = strconv.FormatBool(entry.page.Accoutrement.Explicit.IsEnabled())
 			}
-			finalDate := time.Date(
-				parsedDate.Year(), parsedDate.Month(), parsedDate.Day(),
-				hour, minute, 0, 0, finalLocation)
-
-			// Create the RSS item.
-			items = append(items, rss.Item{
-				XMLName: xml.Name{},
-				Title:   yunyun.RemoveFormatting(yunyun.FancyText(finalTitle)),
-				Link:    conf.Url + string(page.Location),
-				Description: yunyun.FancyText(getDescription(page, conf.Website.DescriptionLength*4)) +
-					" [ Continue reading... ]",
-				Author:    page.Author,
-				Category:  &rss.Category{Value: categoryName, Domain: conf.Url + string(categoryLocation)},
-				Enclosure: &rss.Enclosure{},
-				Guid:      &rss.Guid{Value: conf.Url + string(page.Location), IsPermaLink: true},
-				PubDate:   finalDate.Format(rss.RSSFormat),
-				Source:    &rss.Source{Value: conf.Title, Url: conf.Url},
-			})
 		}
-	}()
-
-	// Try to find the pub date, if none, then reuse the build date
-	buildDate := time.Now().Format(rss.RSSFormat)
-	pubDate := buildDate
-
-	firstPage := gana.First(pages)
-	if firstPage != nil {
-		pubDate = mustDate(firstPage).Format(rss.RSSFormat)
 	}
 
 	// Create the final feed.
@@ -122,46 +66,52 @@ func GenerateRssFeed(conf *alpha.DarknessConfig, rssFilename string, rssDirector
 		Version: rss.RSSVersion,
 		Channel: &rss.Channel{
 			XMLName:        xml.Name{},
-			Title:          yunyun.FancyText(conf.Title),
+			Title:          yunyun.FancyText(title),
 			Link:           conf.Url,
-			Description:    yunyun.FancyText(rootDescription),
+			Description:    yunyun.FancyText(selected.description),
 			Language:       conf.RSS.Language,
 			Copyright:      conf.RSS.Copyright,
 			ManagingEditor: conf.RSS.ManagingEditor,
 			WebMaster:      conf.RSS.WebMaster,
-			PubDate:        pubDate,
-			LastBuildDate:  buildDate,
+			PubDate:        selected.pubDate.Format(rss.RSSFormat),
+			LastBuildDate:  selected.buildDate.Format(rss.RSSFormat),
 			Category:       conf.RSS.Category,
-			Generator:      rssGenerator,
+			Generator:      feedGenerator,
 			Docs:           rss.RSSDocs,
 			TTL:            60,
 			Items:          items,
 		},
 	}
+	if conf.RSS.FullContent {
+		feed.ContentNamespace = rss.ContentNamespace
+	}
+	if selected.hasEnclosures() {
+		feed.ITunesNamespace = rss.ITunesNamespace
+		addPodcast(conf, feed.Channel)
+	}
+
+	return writeXml(conf, rssFilename, feed, dryRun)
+}
 
-	xmlTarget := "stdout"
-	xmlFile := os.Stdout
-	var err error
-	if !dryRun {
-		xmlTarget = string(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(rssFilename)))
-		xmlFile, err = os.Create(filepath.Clean(xmlTarget))
-		if err != nil {
-			logger.Error("Creating file", "path", xmlTarget, "err", err)
-			os.Exit(1)
+// addPodcast fills the channel's podcast elements from the config.
+func addPodcast(conf *alpha.DarknessConfig, channel *rss.Channel) {
+	podcast := conf.RSS.Podcast
+	channel.ITunesAuthor = conf.RSS.DefaultAuthor
+	channel.ITunesExplicit = strconv.FormatBool(podcast.Explicit)
+	channel.ITunesType = podcast.Type
+	if len(podcast.Image) > 0 {
+		image := podcast.Image
+		if !yunyun.UrlRegexp.MatchString(image) {
+			image = conf.Url + strings.TrimPrefix(image, "/")
 		}
+		channel.ITunesImage = &rss.ITunesImage{Href: image}
 	}
-
-	encoder := xml.NewEncoder(xmlFile)
-	encoder.Indent("", "  ")
-	if err := encoder.Encode(feed); err != nil {
-		logger.Error("Encoding to xml", "path", xmlTarget, "err", err)
-		os.Exit(1)
+	if len(podcast.Category) > 0 {
+		channel.ITunesCategory = &rss.ITunesCategory{Text: podcast.Category}
 	}
-	if err := xmlFile.Close(); err != nil {
-		logger.Error("Closing file", "path", xmlTarget, "err", err)
-		os.Exit(1)
+	if len(podcast.OwnerName) > 0 || len(podcast.OwnerEmail) > 0 {
+		channel.ITunesOwner = &rss.ITunesOwner{Name: podcast.OwnerName, Email: podcast.OwnerEmail}
 	}
-	logger.Info("Created rss file", "path", conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(xmlTarget)))
 }
 
 var categoryCache = make(map[string]*yunyun.Page)
@@ -0,0 +1,140 @@
+package misa
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/search"
+)
+
+var (
+	//go:embed search.js
+	searchScript string
+)
+
+// rootShard is the name of the shard with the pages at the root.
+const rootShard = "_root"
+
+// GenerateSearchIndex generates the search index and its widget.
+func GenerateSearchIndex(conf *alpha.DarknessConfig, dryRun bool) error {
+	return writeSearchIndex(conf, hizuru.BuildPagesSimple(conf, nil), dryRun)
+}
+
+// writeSearchIndex writes the search index of the given pages, which is
+// sharded by the top directories if the config asks for it.
+func writeSearchIndex(conf *alpha.DarknessConfig, pages []*yunyun.Page, dryRun bool) error {
+	if err := writeSearchScript(conf, dryRun); err != nil {
+		return err
+	}
+	searchPages := make([]search.Page, 0, len(pages))
+	shards := make(map[string][]search.Page)
+	for _, page := range pages {
+		// Drafts are not published.
+		if page.Accoutrement.Draft.IsEnabled() {
+			continue
+		}
+		searchPage := newSearchPage(conf, page)
+		searchPages = append(searchPages, searchPage)
+		shards[topDirectory(page.Location)] = append(shards[topDirectory(page.Location)], searchPage)
+	}
+	sort.Slice(searchPages, func(i, j int) bool { return searchPages[i].Url < searchPages[j].Url })
+
+	if !conf.Website.SearchShards {
+		return writeJson(conf, puck.SearchIndexFile, &search.Index{Pages: searchPages}, dryRun)
+	}
+
+	index := &search.Index{Shards: make([]search.Shard, 0, len(shards))}
+	for directory, shardPages := range shards {
+		filename := yunyun.JoinRelativePaths(puck.SearchShardsDirectory, yunyun.RelativePathFile(directory+".json"))
+		sort.Slice(shardPages, func(i, j int) bool { return shardPages[i].Url < shardPages[j].Url })
+		if err := writeJson(conf, filename, &search.Index{Pages: shardPages}, dryRun); err != nil {
+			return fmt.Errorf("writing search shard %s: %v", directory, err)
+		}
+		index.Shards = append(index.Shards, search.Shard{
+			Directory: directory,
+			Url:       string(conf.Runtime.Join(filename)),
+		})
+	}
+	sort.Slice(index.Shards, func(i, j int) bool { return index.Shards[i].Directory < index.Shards[j].Directory })
+	return writeJson(conf, puck.SearchIndexFile, index, dryRun)
+}
+
+// newSearchPage returns the plain text of the page and its headings.
+func newSearchPage(conf *alpha.DarknessConfig, page *yunyun.Page) search.Page {
+	result := search.Page{
+		Title: yunyun.RemoveFormatting(yunyun.FancyText(page.Title)),
+		Url:   string(conf.Runtime.Join(yunyun.RelativePathFile(page.Location))),
+	}
+	text := make([]string, 0, len(page.Contents))
+	ids := html.HeadingIDs(page.Contents.Headings())
+	for _, content := range page.Contents {
+		switch {
+		case content.IsHeading():
+			result.Headings = append(result.Headings, search.Heading{
+				Text:   searchText(content.Heading),
+				Anchor: ids[content],
+			})
+		case content.IsParagraph():
+			// Holoscene dates are not the page's text.
+			if !puck.HEregex.MatchString(strings.TrimSpace(content.Paragraph)) {
+				text = append(text, searchText(content.Paragraph))
+			}
+		case content.IsAttentionBlock():
+			text = append(text, searchText(content.AttentionText))
+		case (content.IsList() || content.IsListNumbered()) && !content.IsGallery():
+			for _, item := range content.List {
+				text = append(text, searchText(item.Text))
+			}
+		case content.IsTable():
+			for _, row := range content.Table {
+				for _, cell := range row {
+					text = append(text, searchText(cell))
+				}
+			}
+		}
+	}
+	result.Text = strings.Join(text, " ")
+	return result
+}
+
+// searchText returns the plain text with all whitespace collapsed.
+func searchText(what string) string {
+	return strings.Join(strings.Fields(yunyun.RemoveFormatting(yunyun.FancyText(what))), " ")
+}
+
+// topDirectory returns the first directory of the location.
+func topDirectory(location yunyun.RelativePathDir) string {
+	if location == "." {
+		return rootShard
+	}
+	top, _, _ := strings.Cut(filepath.ToSlash(string(location)), "/")
+	return top
+}
+
+// writeSearchScript writes the search widget, which is skipped on dry runs.
+func writeSearchScript(conf *alpha.DarknessConfig, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	file, target, err := createOutputFile(conf, puck.SearchScriptFile, dryRun)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, searchScript); err != nil {
+		return fmt.Errorf("writing to %s: %v", target, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing file %s: %v", target, err)
+	}
+	logger.Info("Created file", "path", puck.SearchScriptFile)
+	return nil
+}
@@ -0,0 +1,184 @@
+// Darkness search widget, which looks through the search index built
+// by darkness, no external services are needed.
+(function () {
+  "use strict";
+
+  var script = document.currentScript;
+  var indexUrl = script && script.dataset.index;
+  if (!indexUrl) {
+    return;
+  }
+
+  // maxResults is how many pages are shown at once.
+  var maxResults = 10;
+  // snippetRadius is how many characters are shown around the match.
+  var snippetRadius = 60;
+
+  // pages are loaded on the first search.
+  var pages = null;
+  var loading = null;
+
+  function fetchJson(url) {
+    return fetch(url).then(function (response) {
+      if (!response.ok) {
+        throw new Error("fetching " + url + ": " + response.status);
+      }
+      return response.json();
+    });
+  }
+
+  function load() {
+    if (loading) {
+      return loading;
+    }
+    loading = fetchJson(indexUrl).then(function (index) {
+      if (!index.s) {
+        return index.p || [];
+      }
+      return Promise.all(index.s.map(function (shard) {
+        return fetchJson(shard.u).then(function (part) { return part.p || []; });
+      })).then(function (parts) { return [].concat.apply([], parts); });
+    }).then(function (loaded) {
+      pages = loaded;
+      return pages;
+    });
+    return loading;
+  }
+
+  function escapeHtml(text) {
+    return text.replace(/[&<>"']/g, function (c) {
+      return { "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;" }[c];
+    });
+  }
+
+  function snippet(text, term) {
+    var at = text.toLowerCase().indexOf(term);
+    if (at < 0) {
+      return "";
+    }
+    var start = Math.max(0, at - snippetRadius);
+    var end = Math.min(text.length, at + term.length + snippetRadius);
+    return (start > 0 ? "…" : "") +
+      escapeHtml(text.slice(start, at)) +
+      "<mark>" + escapeHtml(text.slice(at, at + term.length)) + "</mark>" +
+      escapeHtml(text.slice(at + term.length, end)) +
+      (end < text.length ? "…" : "");
+  }
+
+  // find returns the pages where every term is found, the best first.
+  function find(query) {
+    var terms = query.toLowerCase().split(/\s+/).filter(Boolean);
+    if (terms.length < 1) {
+      return [];
+    }
+    var results = [];
+    pages.forEach(function (page) {
+      var title = page.t.toLowerCase();
+      var text = page.x.toLowerCase();
+      var score = 0;
+      var url = page.u;
+      var found = terms.every(function (term) {
+        var termScore = 0;
+        if (title.indexOf(term) >= 0) {
+          termScore += 10;
+        }
+        (page.h || []).forEach(function (heading) {
+          if (heading.t.toLowerCase().indexOf(term) >= 0) {
+            termScore += 5;
+            if (url === page.u) {
+              url = page.u + "#" + heading.a;
+            }
+          }
+        });
+        if (text.indexOf(term) >= 0) {
+          termScore += 1;
+        }
+        score += termScore;
+        return termScore > 0;
+      });
+      if (found) {
+        results.push({ page: page, url: url, score: score, snippet: snippet(page.x, terms[0]) });
+      }
+    });
+    results.sort(function (a, b) { return b.score - a.score; });
+    return results.slice(0, maxResults);
+  }
+
+  function render(list, results, query) {
+    if (query.trim().length < 1) {
+      list.innerHTML = "";
+      list.hidden = true;
+      return;
+    }
+    list.hidden = false;
+    if (results.length < 1) {
+      list.innerHTML = '<li class="search-empty">Nothing found</li>';
+      return;
+    }
+    list.innerHTML = results.map(function (result) {
+      return '<li><a href="' + escapeHtml(result.url) + '">' + escapeHtml(result.page.t) + "</a>" +
+        (result.snippet ? '<div class="search-snippet">' + result.snippet + "</div>" : "") + "</li>";
+    }).join("");
+  }
+
+  // style is the minimal look of the widget, themes can override it.
+  var style =
+    ".search{position:relative;display:inline-block}" +
+    ".search-results{position:absolute;right:0;z-index:10;width:24em;max-height:70vh;overflow:auto;" +
+    "margin:0;padding:0;list-style:none;background:#fff;color:#000;border:1px solid #ccc}" +
+    ".search-results li{padding:.4em .6em;border-bottom:1px solid #eee}" +
+    ".search-snippet{font-size:.85em;opacity:.8}";
+
+  function create() {
+    var sheet = document.createElement("style");
+    sheet.textContent = style;
+    document.head.appendChild(sheet);
+
+    var container = document.createElement("div");
+    container.className = "search";
+    container.innerHTML =
+      '<input type="search" class="search-input" placeholder="Search (/)" aria-label="Search" autocomplete="off">' +
+      '<ul class="search-results" hidden></ul>';
+    var input = container.querySelector("input");
+    var list = container.querySelector("ul");
+
+    var update = function () {
+      var query = input.value;
+      load().then(function () {
+        // Only show the results of the latest query.
+        if (query === input.value) {
+          render(list, find(query), query);
+        }
+      }).catch(function (err) {
+        list.hidden = false;
+        list.innerHTML = '<li class="search-empty">Search is unavailable</li>';
+        console.error(err);
+      });
+    };
+    input.addEventListener("input", update);
+    input.addEventListener("focus", function () { load().catch(function () {}); });
+    input.addEventListener("keydown", function (event) {
+      if (event.key === "Escape") {
+        input.value = "";
+        render(list, [], "");
+        input.blur();
+      }
+    });
+    document.addEventListener("keydown", function (event) {
+      if (event.key === "/" && document.activeElement !== input &&
+        !/^(INPUT|TEXTAREA|SELECT)$/.test(document.activeElement.tagName)) {
+        event.preventDefault();
+        input.focus();
+      }
+    });
+
+    var header = document.querySelector(".header .menu") || document.body;
+    header.appendChild(container);
+  }
+
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", create);
+  } else {
+    create();
+  }
+})();
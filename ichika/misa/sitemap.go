@@ -0,0 +1,90 @@
+package misa
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/emilia/narumi"
+	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/darkness/yunyun/sitemap"
+	"github.com/thecsw/gana"
+)
+
+// GenerateSitemap generates a sitemap of all the published pages, which
+// becomes a sitemap index of multiple sitemaps if there are too many pages.
+func GenerateSitemap(conf *alpha.DarknessConfig, sitemapFilename yunyun.RelativePathFile, dryRun bool) error {
+	return writeSitemap(conf, hizuru.BuildPagesSimple(conf, nil), sitemapFilename, dryRun)
+}
+
+// writeSitemap writes the sitemap of the given pages.
+func writeSitemap(conf *alpha.DarknessConfig, pages []*yunyun.Page, sitemapFilename yunyun.RelativePathFile, dryRun bool) error {
+	urls := make([]sitemap.URL, 0, len(pages))
+	func() {
+		defer puck.Stopwatch("Built sitemap urls", "num", len(pages)).Record()
+		for _, page := range pages {
+			// Drafts are not published.
+			if page.Accoutrement.Draft.IsEnabled() {
+				continue
+			}
+			url := sitemap.URL{Loc: pageUrl(conf, page)}
+			if date, ok := narumi.ConvertHoloscene(page.Date); ok {
+				url.LastMod = date.Format(sitemap.LastModFormat)
+			}
+			urls = append(urls, url)
+		}
+	}()
+	sort.Slice(urls, func(i, j int) bool { return urls[i].Loc < urls[j].Loc })
+
+	// Everything fits into one sitemap.
+	if len(urls) <= sitemap.MaxURLs {
+		return writeXml(conf, sitemapFilename, &sitemap.URLSet{
+			Namespace: sitemap.Namespace,
+			URLs:      urls,
+		}, dryRun)
+	}
+
+	// Otherwise, split them up and list them in the index.
+	index := &sitemap.Index{Namespace: sitemap.Namespace}
+	ext := filepath.Ext(string(sitemapFilename))
+	for i := 0; i*sitemap.MaxURLs < len(urls); i++ {
+		filename := yunyun.RelativePathFile(fmt.Sprintf("%s-%d%s",
+			strings.TrimSuffix(string(sitemapFilename), ext), i+1, ext))
+		chunk := urls[i*sitemap.MaxURLs : gana.Min((i+1)*sitemap.MaxURLs, len(urls))]
+		if err := writeXml(conf, filename, &sitemap.URLSet{
+			Namespace: sitemap.Namespace,
+			URLs:      chunk,
+		}, dryRun); err != nil {
+			return err
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemap.Sitemap{
+			Loc:     conf.Url + filepath.ToSlash(string(filename)),
+			LastMod: latestLastMod(chunk),
+		})
+	}
+	return writeXml(conf, sitemapFilename, index, dryRun)
+}
+
+// pageUrl returns the full url of the page.
+func pageUrl(conf *alpha.DarknessConfig, page *yunyun.Page) string {
+	if page.Location == "." {
+		return conf.Url
+	}
+	return conf.Url + filepath.ToSlash(string(page.Location))
+}
+
+// latestLastMod returns the latest modification date of the urls.
+func latestLastMod(urls []sitemap.URL) string {
+	latest := ""
+	for _, url := range urls {
+		// The format sorts the same way as the dates.
+		if url.LastMod > latest {
+			latest = url.LastMod
+		}
+	}
+	return latest
+}
@@ -0,0 +1,90 @@
+package misa
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// tagGroup is all the pages that share a tag.
+type tagGroup struct {
+	// name is how the tag was first written.
+	name string
+	// slug is the tag's directory name.
+	slug string
+	// pages are the tagged pages.
+	pages []*yunyun.Page
+}
+
+// GenerateTagPages generates the page of every tag, which lists the tagged
+// pages by their dates, and the index of all the tags with their counts.
+func GenerateTagPages(conf *alpha.DarknessConfig, dryRun bool) error {
+	if len(conf.Website.Tags) < 1 {
+		return fmt.Errorf("no tags directory is set in the config")
+	}
+	return writeTagPages(conf, hizuru.BuildPagesSimple(conf, nil), dryRun)
+}
+
+// writeTagPages writes the tag pages of the given pages.
+func writeTagPages(conf *alpha.DarknessConfig, allPages []*yunyun.Page, dryRun bool) error {
+	// Group the pages by their tags' slugs, so "Go" and "go" are the same.
+	groups := make(map[string]*tagGroup)
+	sources := make(map[yunyun.RelativePathDir]bool, len(allPages))
+	for _, page := range allPages {
+		sources[page.Location] = true
+		// Drafts are not published.
+		if page.Accoutrement.Draft.IsEnabled() {
+			continue
+		}
+		for _, tag := range page.Tags {
+			slug := yunyun.TagSlug(tag)
+			if _, ok := groups[slug]; !ok {
+				groups[slug] = &tagGroup{name: tag, slug: slug}
+			}
+			groups[slug].pages = append(groups[slug].pages, page)
+		}
+	}
+	sorted := make([]*tagGroup, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, group)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].slug < sorted[j].slug })
+
+	// Tag pages written by hand take precedence over the generated ones.
+	write := func(page *yunyun.Page) error {
+		if sources[page.Location] {
+			logger.Debug("Skipping tag page with a source", "location", page.Location)
+			return nil
+		}
+		return writePage(conf, page, dryRun)
+	}
+
+	index := make([]yunyun.ListItem, 0, len(sorted))
+	for _, group := range sorted {
+		location := yunyun.RelativePathDir(yunyun.JoinRelativePaths(conf.Website.Tags, yunyun.RelativePathFile(group.slug)))
+		sortByDate(group.pages)
+		items := make([]yunyun.ListItem, 0, len(group.pages))
+		for _, page := range group.pages {
+			items = append(items, pageListItem(conf, page))
+		}
+		if err := write(newGeneratedPage(location, "Tagged "+group.name, yunyun.Contents{
+			{Type: yunyun.TypeList, List: items},
+		})); err != nil {
+			return fmt.Errorf("writing tag page %s: %v", group.name, err)
+		}
+		index = append(index, yunyun.ListItem{Level: 1, Text: fmt.Sprintf("[[%s][%s]] (%d)",
+			conf.Runtime.Join(yunyun.RelativePathFile(location)), group.name, len(group.pages))})
+	}
+
+	contents := yunyun.Contents{{Type: yunyun.TypeList, List: index}}
+	if len(index) < 1 {
+		contents = yunyun.Contents{{Type: yunyun.TypeParagraph, Paragraph: "No pages are tagged yet."}}
+	}
+	if err := write(newGeneratedPage(conf.Website.Tags, "Tags", contents)); err != nil {
+		return fmt.Errorf("writing tags index: %v", err)
+	}
+	return nil
+}
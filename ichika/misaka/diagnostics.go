@@ -0,0 +1,41 @@
+package misaka
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+var (
+	// diagnostics are the problems found during the build.
+	diagnostics = yunyun.Diagnostics{}
+	// diagnosticsLock guards the diagnostics.
+	diagnosticsLock = sync.Mutex{}
+)
+
+// RecordDiagnostics records the problems found during the build.
+func RecordDiagnostics(found ...yunyun.Diagnostic) {
+	diagnosticsLock.Lock()
+	defer diagnosticsLock.Unlock()
+	diagnostics = append(diagnostics, found...)
+}
+
+// RecordFailure records the error as a failure of the file, where
+// diagnostics are recorded as they are.
+func RecordFailure(filename yunyun.RelativePathFile, err error) {
+	found := yunyun.Diagnostics{}
+	if !errors.As(err, &found) {
+		found.Fail(filename, "%v", err)
+	}
+	RecordDiagnostics(found...)
+}
+
+// TakeDiagnostics returns the recorded problems and forgets them.
+func TakeDiagnostics() yunyun.Diagnostics {
+	diagnosticsLock.Lock()
+	defer diagnosticsLock.Unlock()
+	taken := diagnostics
+	diagnostics = yunyun.Diagnostics{}
+	return taken
+}
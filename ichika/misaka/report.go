@@ -90,7 +90,7 @@ func buildCSVReport(conf *alpha.DarknessConfig) *bytes.Buffer {
 		exportTime := int64(report[exportIndex])
 		writeTime := int64(report[writeIndex])
 		totalTime := readTime + parseTime + exportTime + writeTime
-		fullpath := yunyun.FullPathFile(conf.Project.InputFilenameToOutput(inputFile))
+		fullpath := yunyun.FullPathFile(conf.InputFilenameToOutput(inputFile))
 		rei.Try(writer.Write([]string{
 			strconv.Itoa(num),
 			string(conf.Runtime.WorkDir.Rel(inputFile)),
@@ -0,0 +1,163 @@
+package ichika
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thecsw/darkness/emilia/puck"
+)
+
+const (
+	// liveReloadPath is where the browsers listen for rebuilds.
+	liveReloadPath = "/.darkness/reload"
+
+	// liveReloadScript reloads the page on rebuilds and scrolls back to the
+	// heading that was at the top of the screen, using the headings' ids.
+	liveReloadScript = `
+<script>
+(function () {
+  const key = "darkness-reload-heading";
+  const saved = sessionStorage.getItem(key);
+  if (saved !== null) {
+    sessionStorage.removeItem(key);
+    const [id, offset] = JSON.parse(saved);
+    const heading = id && document.getElementById(id);
+    window.addEventListener("load", function () {
+      if (heading) {
+        heading.scrollIntoView();
+        window.scrollBy(0, offset);
+      } else {
+        window.scrollTo(0, offset);
+      }
+    });
+  }
+  const events = new EventSource("%s");
+  events.addEventListener("reload", function () {
+    let closest = null;
+    for (const heading of document.querySelectorAll("h1[id], h2[id], h3[id], h4[id], h5[id], h6[id]")) {
+      if (heading.getBoundingClientRect().top > 1) {
+        break;
+      }
+      closest = heading;
+    }
+    const state = closest === null
+      ? ["", window.scrollY]
+      : [closest.id, -closest.getBoundingClientRect().top];
+    sessionStorage.setItem(key, JSON.stringify(state));
+    window.location.reload();
+  });
+})();
+</script>
+`
+)
+
+// liveReloader tells the connected browsers to reload after rebuilds.
+type liveReloader struct {
+	// clients are the channels of the connected browsers.
+	clients map[chan struct{}]struct{}
+	// lock guards the clients.
+	lock sync.Mutex
+}
+
+// newLiveReloader returns a new live reloader with no clients.
+func newLiveReloader() *liveReloader {
+	return &liveReloader{clients: map[chan struct{}]struct{}{}}
+}
+
+// Reload notifies all the connected browsers.
+func (l *liveReloader) Reload() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for client := range l.clients {
+		// Don't block on browsers that already have a reload pending.
+		select {
+		case client <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ServeHTTP streams the reload events to a browser.
+func (l *liveReloader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	// The connection lives for as long as the page is open.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		puck.Logger.Debug("Clearing live reload deadline", "err", err)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	client := make(chan struct{}, 1)
+	l.lock.Lock()
+	l.clients[client] = struct{}{}
+	l.lock.Unlock()
+	defer func() {
+		l.lock.Lock()
+		delete(l.clients, client)
+		l.lock.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-client:
+			if _, err := fmt.Fprint(w, "event: reload\ndata: \n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// Inject is a middleware that adds the live reload script to html pages.
+func (l *liveReloader) Inject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buffered := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+		body := buffered.body.Bytes()
+		if strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+			script := []byte(fmt.Sprintf(liveReloadScript, liveReloadPath))
+			if i := bytes.LastIndex(body, []byte("</body>")); i >= 0 {
+				body = append(body[:i:i], append(script, body[i:]...)...)
+			} else {
+				body = append(body, script...)
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		}
+		w.WriteHeader(buffered.status)
+		if _, err := w.Write(body); err != nil {
+			puck.Logger.Debug("Writing response", "path", r.URL.Path, "err", err)
+		}
+	})
+}
+
+// bufferedResponseWriter holds on to the response, so it can be modified.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	// status is the status code of the response.
+	status int
+	// body is the body of the response.
+	body bytes.Buffer
+}
+
+// WriteHeader saves the status code for later.
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+// Write saves the body for later.
+func (b *bufferedResponseWriter) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
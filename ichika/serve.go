@@ -1,11 +1,14 @@
 package ichika
 
 import (
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -14,9 +17,11 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/karrick/godirwalk"
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/emilia/puck"
-	"github.com/thecsw/darkness/ichika/hizuru"
+	"github.com/thecsw/darkness/export/html"
+	"github.com/thecsw/darkness/ichika/akane"
 	"github.com/thecsw/darkness/ichika/kuroko"
 	"github.com/thecsw/darkness/yunyun"
 )
@@ -25,27 +30,45 @@ const (
 	// defaultServePort is the default port used when serving
 	// local files.
 	defaultServePort = 8080
+	// defaultServeHost is the default host to listen on.
+	defaultServeHost = "127.0.0.1"
 )
 
-// ServeCommandFunc builds the website, local serves it on 8080 and then
-// cleans the files.
+// ServeCommandFunc builds the website into a temporary directory, serves
+// it on 8080 and then cleans the directory up.
 func ServeCommandFunc() {
 	serveCmd := darknessFlagset(serveCommand)
 	port := serveCmd.Int("port", defaultServePort, "port number to use")
+	host := serveCmd.String("host", defaultServeHost, "host to listen on, 0.0.0.0 for the whole network")
 	noBrowser := serveCmd.Bool("no-browser", false, "do not open the browser")
+	openCmd := serveCmd.String("open-cmd", defaultOpenCommand(), "command to open the browser with")
+	keep := serveCmd.Bool("keep", false, "keep the built files when shutting down")
 	options := getAlphaOptions(serveCmd)
-	options.Url = "http://127.0.0.1:" + strconv.Itoa(*port)
+	address := net.JoinHostPort(*host, strconv.Itoa(*port))
+	options.Url = "http://" + net.JoinHostPort(reachableHost(*host), strconv.Itoa(*port))
 	// Override the output extension to .html
 	options.OutputExtension = puck.ExtensionHtml
+
+	// Build into a temporary directory, so the sources are never touched.
+	outputDirectory, err := os.MkdirTemp("", "darkness-serve-")
+	if err != nil {
+		puck.Logger.Fatalf("creating a temporary directory: %v", err)
+	}
+	options.OutputDirectory = outputDirectory
 	// emilia.InitDarkness(options)
 	conf := alpha.BuildConfig(options)
 
 	puck.Logger.SetPrefix("Server 🍩 ")
 
-	build(conf)
-	// disable akane after the first build
+	// The build cache would write into the sources.
+	kuroko.Cacheless = true
+	if err := build(conf); err != nil {
+		puck.Logger.Error("Building", "err", err)
+	}
+	// Akane has published her work with the first build, disable her
+	// for the rebuilds.
 	kuroko.Akaneless = true
-	puck.Logger.Print("Serving the files", "url", options.Url)
+	puck.Logger.Print("Serving the files", "url", options.Url, "dir", outputDirectory)
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -54,15 +77,19 @@ func ServeCommandFunc() {
 
 	// Set up the server's timeouts.
 	srv := &http.Server{
-		Addr:              "127.0.0.1:" + strconv.Itoa(*port),
+		Addr:              address,
 		Handler:           r,
 		ReadTimeout:       5 * time.Second,
 		ReadHeaderTimeout: 1 * time.Second,
 		WriteTimeout:      10 * time.Second,
 	}
 
+	// Browsers reload the pages after each rebuild.
+	reloader := newLiveReloader()
+	r.Get(liveReloadPath, reloader.ServeHTTP)
+
 	// Tune it to serve local files.
-	fileServer(r, "/", http.Dir(string(conf.Runtime.WorkDir)))
+	fileServer(r.With(reloader.Inject), "/", http.Dir(string(conf.Runtime.OutputDir)))
 
 	// Spin the local server up.
 	go func() {
@@ -70,29 +97,73 @@ func ServeCommandFunc() {
 	}()
 
 	// File watcher will rebuild dir if any files change.
-	go launchWatcher(conf)
+	go launchWatcher(conf, options, reloader.Reload)
 	puck.Logger.Print("Launched file watcher")
 
-	// Try to open the local server with `open` command.
+	// Try to open the local server with the open command.
 	if !*noBrowser {
 		time.Sleep(500 * time.Millisecond)
-		if err := exec.Command("open", options.Url).Run(); err != nil {
-			puck.Logger.Error("Couldn't open the browser", err)
-		}
+		openBrowser(*openCmd, options.Url)
 	}
 
 	sigint := make(chan os.Signal, 1)
 	signal.Notify(sigint, os.Interrupt)
 	<-sigint
-	puck.Logger.Print("Shutting down the server + cleaning up")
-	isQuietMegumin = true
-	removeOutputFiles(conf)
+	if *keep {
+		puck.Logger.Print("Shutting down the server, keeping the files", "dir", outputDirectory)
+	} else {
+		puck.Logger.Print("Shutting down the server + cleaning up")
+		if err := os.RemoveAll(outputDirectory); err != nil {
+			puck.Logger.Error("Removing the built files", "dir", outputDirectory, "err", err)
+		}
+	}
 	puck.Logger.Print("farewell")
 }
 
+// defaultOpenCommand returns the command that opens urls on this system.
+func defaultOpenCommand() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "open"
+	case "windows":
+		return "rundll32 url.dll,FileProtocolHandler"
+	default:
+		return "xdg-open"
+	}
+}
+
+// openBrowser opens the url with the command, which can have arguments.
+func openBrowser(command, url string) {
+	fields := strings.Fields(command)
+	if len(fields) < 1 {
+		return
+	}
+	if err := exec.Command(fields[0], append(fields[1:], url)...).Start(); err != nil {
+		puck.Logger.Error("Couldn't open the browser", "cmd", command, "err", err)
+	}
+}
+
+// reachableHost returns the host that browsers should use to reach the server,
+// which is this machine's network address if listening on all interfaces.
+func reachableHost(host string) string {
+	if ip := net.ParseIP(host); host != "" && (ip == nil || !ip.IsUnspecified()) {
+		return host
+	}
+	addresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return defaultServeHost
+	}
+	for _, address := range addresses {
+		if ipNet, ok := address.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	return defaultServeHost
+}
+
 // launchWatcher watches for any file creations, changes, modifications, deletions
-// and rebuilds the directory as that happens.
-func launchWatcher(conf *alpha.DarknessConfig) {
+// and rebuilds the affected files as that happens.
+func launchWatcher(conf *alpha.DarknessConfig, options alpha.Options, onRebuild func()) {
 	// Create new watcher.
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -109,28 +180,13 @@ func launchWatcher(conf *alpha.DarknessConfig) {
 					puck.Logger.Warn("stopped watching")
 					return
 				}
-				filename := string(conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(event.Name)))
-				if strings.HasSuffix(filename, conf.Project.Output) ||
-					strings.HasPrefix(filepath.Base(filename), `.`) {
-					continue
-				}
 				// Skip CHMOD events that IDE and editors do by default
 				if event.Has(fsnotify.Chmod) {
 					continue
 				}
-				if event.Has(fsnotify.Write) {
-					puck.Logger.Warn("A file was modified", "path", filename)
-				}
-				if event.Has(fsnotify.Create) {
-					puck.Logger.Warn("A file was created", "path", filename)
-				}
-				if event.Has(fsnotify.Remove) {
-					puck.Logger.Warn("A file was removed", "path", filename)
+				if handleWatcherEvent(conf, options, watcher, event) {
+					onRebuild()
 				}
-				if event.Has(fsnotify.Rename) {
-					puck.Logger.Warn("A file was renamed", "path", filename)
-				}
-				build(conf)
 			case err, ok := <-watcher.Errors:
 				if !ok {
 					puck.Logger.Warn("Watcher is leaving")
@@ -141,13 +197,13 @@ func launchWatcher(conf *alpha.DarknessConfig) {
 		}
 	}()
 
-	// start adding all the source files
-	for _, inputFilenameToWatch := range hizuru.FindFilesByExtSimple(conf) {
-		err = watcher.Add(string(inputFilenameToWatch))
-		if err != nil {
+	// Watch the config and all the directories, so new files are seen.
+	if !isInWatchedDirectory(conf, conf.Runtime.ConfigPath) {
+		if err := watcher.Add(conf.Runtime.ConfigPath); err != nil {
 			log.Fatal(err)
 		}
 	}
+	watchDirectory(conf, watcher, string(conf.Runtime.WorkDir))
 	puck.Logger.Print("Listening to file changes", "num", len(watcher.WatchList()), "dir", conf.Runtime.WorkDir)
 
 	puck.Logger.Print("Press Ctrl-C to stop the server")
@@ -155,6 +211,134 @@ func launchWatcher(conf *alpha.DarknessConfig) {
 	<-make(chan struct{})
 }
 
+// handleWatcherEvent rebuilds whatever the event has touched, returns
+// true if anything might have changed in the built site.
+func handleWatcherEvent(conf *alpha.DarknessConfig, options alpha.Options, watcher *fsnotify.Watcher, event fsnotify.Event) bool {
+	// The config changes everything, so reload it and rebuild the site.
+	if samePath(event.Name, conf.Runtime.ConfigPath) {
+		puck.Logger.Warn("The config was modified, rebuilding everything", "path", event.Name)
+		// Editors like to replace the file, which drops the watch.
+		if (event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)) &&
+			!isInWatchedDirectory(conf, conf.Runtime.ConfigPath) {
+			if err := watcher.Add(conf.Runtime.ConfigPath); err != nil {
+				puck.Logger.Error("Watching the config again", "err", err)
+			}
+		}
+		*conf = *alpha.BuildConfig(options)
+		html.ForgetTheme(conf)
+		if err := build(conf); err != nil {
+			puck.Logger.Error("Rebuilding", "err", err)
+		}
+		return true
+	}
+	// The theme's templates change every page, so load them again and
+	// rebuild the site.
+	if conf.IsThemeDirectory(event.Name) ||
+		(conf.IsThemeDirectory(filepath.Dir(event.Name)) && filepath.Ext(event.Name) == puck.ExtensionHtml) {
+		puck.Logger.Warn("The theme was modified, rebuilding everything", "path", conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(event.Name)))
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			watchDirectory(conf, watcher, event.Name)
+		}
+		html.ForgetTheme(conf)
+		if err := build(conf); err != nil {
+			puck.Logger.Error("Rebuilding", "err", err)
+		}
+		return true
+	}
+	filename := string(conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(event.Name)))
+	if strings.HasPrefix(filepath.Base(filename), `.`) ||
+		conf.IsOutputDirectory(event.Name) ||
+		(!conf.HasOutputDirectory() && strings.HasSuffix(filename, conf.Project.Output)) {
+		return false
+	}
+	switch {
+	case event.Has(fsnotify.Write):
+		puck.Logger.Warn("A file was modified", "path", filename)
+	case event.Has(fsnotify.Create):
+		puck.Logger.Warn("A file was created", "path", filename)
+		// New directories have to be watched and built.
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			watchDirectory(conf, watcher, event.Name)
+			buildDirectory(conf, event.Name)
+			return true
+		}
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		puck.Logger.Warn("A file was removed", "path", filename)
+		// Remove the output of the input that has gone away.
+		if conf.Project.Input.HasFile(event.Name) {
+			if err := os.Remove(conf.InputFilenameToOutput(yunyun.FullPathFile(event.Name))); err != nil && !os.IsNotExist(err) {
+				puck.Logger.Error("Removing the output", "path", filename, "err", err)
+			}
+		}
+		return true
+	}
+	// Inputs get rebuilt and everything else is an asset.
+	if !conf.Project.Input.HasFile(event.Name) {
+		akane.PublishAssets(conf)
+		return true
+	}
+	if err := buildFile(conf, yunyun.FullPathFile(event.Name)); err != nil {
+		puck.Logger.Error("Rebuilding", "path", filename, "err", err)
+		return false
+	}
+	return true
+}
+
+// samePath returns true if both paths point to the same file.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// isInWatchedDirectory returns true if the file is right in the work directory,
+// which is always watched.
+func isInWatchedDirectory(conf *alpha.DarknessConfig, filename string) bool {
+	return samePath(filepath.Dir(filename), string(conf.Runtime.WorkDir))
+}
+
+// watchDirectory adds the directory and all of its children to the watcher.
+func watchDirectory(conf *alpha.DarknessConfig, watcher *fsnotify.Watcher, dir string) {
+	if err := godirwalk.Walk(dir, &godirwalk.Options{
+		Unsorted: true,
+		Callback: func(osPathname string, de *godirwalk.Dirent) error {
+			if !de.IsDir() {
+				return nil
+			}
+			// Don't watch hidden directories or the built site.
+			if (osPathname != dir && strings.HasPrefix(de.Name(), ".")) || conf.IsOutputDirectory(osPathname) ||
+				(conf.Project.ExcludeEnabled && conf.Project.ExcludeRegex.MatchString(osPathname+"/")) {
+				return filepath.SkipDir
+			}
+			if err := watcher.Add(osPathname); err != nil {
+				return fmt.Errorf("watching %s: %v", osPathname, err)
+			}
+			return nil
+		},
+	}); err != nil {
+		puck.Logger.Error("Watching directories", "dir", dir, "err", err)
+	}
+}
+
+// buildDirectory builds all the input files found in the directory.
+func buildDirectory(conf *alpha.DarknessConfig, dir string) {
+	if err := godirwalk.Walk(dir, &godirwalk.Options{
+		Unsorted: true,
+		Callback: func(osPathname string, de *godirwalk.Dirent) error {
+			if de.IsDir() || !conf.Project.Input.HasFile(osPathname) {
+				return nil
+			}
+			if err := buildFile(conf, yunyun.FullPathFile(osPathname)); err != nil {
+				puck.Logger.Error("Building", "path", osPathname, "err", err)
+			}
+			return nil
+		},
+	}); err != nil {
+		puck.Logger.Error("Building directory", "dir", dir, "err", err)
+	}
+	akane.PublishAssets(conf)
+}
+
 // fileServer conveniently sets up a http.FileServer handler to serve
 // static files from a http.FileSystem.
 // Taken from https://github.com/go-chi/chi/blob/master/_examples/fileserver/main.go
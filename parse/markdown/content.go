@@ -0,0 +1,336 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/thecsw/darkness/yunyun"
+)
+
+// isHeader returns a non-nil object if the line is an ATX header
+func isHeader(line string, footnotes map[string]string) *yunyun.Content {
+	matches := headingRegexp.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	heading, id := extractHeadingId(matches[2])
+	return &yunyun.Content{
+		Type:         yunyun.TypeHeading,
+		HeadingLevel: uint32(len(matches[1])),
+		Heading:      convertInline(heading, footnotes),
+		HeadingId:    id,
+	}
+}
+
+// extractHeadingId splits `Heading {#id}` into the heading and its custom id,
+// where the ids that can't be used as they are stay in the heading.
+func extractHeadingId(heading string) (string, string) {
+	matches := headingIdRegexp.FindStringSubmatchIndex(heading)
+	if matches == nil || !yunyun.IsValidHeadingId(heading[matches[2]:matches[3]]) {
+		return heading, ""
+	}
+	return heading[:matches[0]], heading[matches[2]:matches[3]]
+}
+
+// isSetextUnderline returns the heading level if the line underlines
+// the previous paragraph, zero otherwise.
+func isSetextUnderline(line string) uint32 {
+	if !setextRegexp.MatchString(line) {
+		return 0
+	}
+	if strings.HasPrefix(line, "=") {
+		return 1
+	}
+	return 2
+}
+
+// isHorizonalLine returns true if we are currently reading a horizontal line,
+// false otherwise.
+func isHorizonalLine(line string) bool {
+	return horizontalLineRegexp.MatchString(line)
+}
+
+// isFence returns the fence marker if the line opens a fenced code block,
+// empty string otherwise.
+func isFence(line string) string {
+	switch {
+	case strings.HasPrefix(line, fenceBackticks):
+		return line[:len(line)-len(strings.TrimLeft(line, "`"))]
+	case strings.HasPrefix(line, fenceTildes):
+		return line[:len(line)-len(strings.TrimLeft(line, "~"))]
+	}
+	return ""
+}
+
+// isFenceEnd returns true if the line closes the block opened by `fence`.
+func isFenceEnd(line, fence string) bool {
+	return strings.HasPrefix(line, fence) &&
+		len(strings.Trim(line, fence[:1])) == 0
+}
+
+// extractSourceCodeLanguage extracts language `LANG` from ```LANG.
+func extractSourceCodeLanguage(line, fence string) string {
+	info := strings.Fields(strings.TrimPrefix(line, fence))
+	if len(info) < 1 {
+		return ""
+	}
+	// Some people like to write ```{.python} or ```{python}
+	return strings.Trim(info[0], "{}.")
+}
+
+// isHtmlBlock returns true if the line starts a raw html block.
+func isHtmlBlock(line string) bool {
+	return htmlBlockRegexp.MatchString(line)
+}
+
+// isQuote returns true if the line is a part of a block quote.
+func isQuote(line string) bool {
+	return strings.HasPrefix(line, quotePrefix)
+}
+
+// extractQuote strips the quote marker from the line.
+func extractQuote(line string) string {
+	line = strings.TrimPrefix(line, quotePrefix)
+	return strings.TrimPrefix(line, " ")
+}
+
+// isTable returns true if we are currently reading a table, false otherwise.
+func isTable(line string) bool {
+	return strings.HasPrefix(line, tablePrefix)
+}
+
+// isTableHeaderDelimeter returns true if we are currently reading a table
+// header delimiter, false otherwise.
+func isTableHeaderDelimeter(line string) bool {
+	return tableDelimiterRegexp.MatchString(line)
+}
+
+// extractTableRow splits the table row into its trimmed cells.
+func extractTableRow(line string, footnotes map[string]string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, tablePrefix), tablePrefix)
+	// Escaped pipes are allowed within cells.
+	cells := strings.Split(strings.ReplaceAll(line, `\|`, string(placeholderStart)), tablePrefix)
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, string(placeholderStart), tablePrefix)
+		cells[i] = convertInlineCell(strings.TrimSpace(cell), footnotes)
+	}
+	return cells
+}
+
+// listItem is a matched list line.
+type listItem struct {
+	// indent is the width of the whitespace before the marker.
+	indent int
+	// numbered is true if the marker is a number.
+	numbered bool
+	// text is the text after the marker.
+	text string
+}
+
+// isList returns a non-nil item if the line is a list item.
+func isList(rawLine string) *listItem {
+	matches := listItemRegexp.FindStringSubmatch(rawLine)
+	if matches == nil {
+		return nil
+	}
+	return &listItem{
+		indent:   len(strings.ReplaceAll(matches[1], "\t", "    ")),
+		numbered: !strings.ContainsAny(matches[2], "-*+"),
+		text:     matches[3],
+	}
+}
+
+// isIndented returns true if the line starts with whitespace.
+func isIndented(rawLine string) bool {
+	return strings.HasPrefix(rawLine, " ") || strings.HasPrefix(rawLine, "\t")
+}
+
+// isComment returns true if the line is a one-line html comment.
+func isComment(line string) bool {
+	return strings.HasPrefix(line, commentStart) && strings.HasSuffix(line, commentEnd)
+}
+
+// getLink returns a non-nil object if the text is a standalone link,
+// image, or a bare url.
+func getLink(text string, footnotes map[string]string) *yunyun.Content {
+	if matches := imageRegexp.FindStringSubmatch(text); matches != nil && len(matches[0]) == len(text) {
+		return &yunyun.Content{
+			Type:            yunyun.TypeLink,
+			Link:            matches[2],
+			LinkTitle:       convertInline(matches[1], footnotes),
+			LinkDescription: orDefault(matches[3], matches[1]),
+			Attributes:      imageAttribute,
+		}
+	}
+	if matches := linkRegexp.FindStringSubmatch(text); matches != nil && len(matches[0]) == len(text) {
+		return &yunyun.Content{
+			Type:            yunyun.TypeLink,
+			Link:            matches[2],
+			LinkTitle:       convertInline(matches[1], footnotes),
+			LinkDescription: orDefault(matches[3], matches[1]),
+		}
+	}
+	if matches := autolinkRegexp.FindStringSubmatch(text); matches != nil && len(matches[0]) == len(text) {
+		text = matches[1]
+	}
+	if !strings.ContainsAny(text, " \t") && yunyun.UrlRegexp.FindString(text) == text {
+		return &yunyun.Content{
+			Type:            yunyun.TypeLink,
+			Link:            text,
+			LinkTitle:       text,
+			LinkDescription: text,
+		}
+	}
+	return nil
+}
+
+// formParagraph builds a proper paragraph-oriented `Content` object.
+func formParagraph(text string, footnotes map[string]string, options yunyun.Bits) *yunyun.Content {
+	return &yunyun.Content{
+		Type:      yunyun.TypeParagraph,
+		Paragraph: convertInline(strings.TrimSpace(text), footnotes),
+		Options:   options,
+	}
+}
+
+// joinLines joins the lines of a paragraph, keeping the hard line breaks.
+func joinLines(lines []string) string {
+	for i, line := range lines[:len(lines)-1] {
+		// Two trailing spaces is a hard break, yunyun uses backslashes.
+		if strings.HasSuffix(line, hardBreakSpaces) {
+			lines[i] = strings.TrimRight(line, " ") + ` \`
+			continue
+		}
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, " "))
+}
+
+// inline holds the spans that should not be touched by emphasis rewrites.
+type inline struct {
+	protected []string
+}
+
+// protect stores the given span and returns its placeholder.
+func (spans *inline) protect(what string) string {
+	spans.protected = append(spans.protected, what)
+	return string(placeholderStart) + strconv.Itoa(len(spans.protected)-1) + string(placeholderEnd)
+}
+
+// restore puts the protected spans back in place of their placeholders,
+// protected spans can have placeholders of their own (like links' text).
+func (spans *inline) restore(text string) string {
+	for placeholderRegexp.MatchString(text) {
+		text = placeholderRegexp.ReplaceAllStringFunc(text, func(what string) string {
+			index, _ := strconv.Atoi(placeholderRegexp.FindStringSubmatch(what)[1])
+			return spans.protected[index]
+		})
+	}
+	return text
+}
+
+// convertInline rewrites markdown's inline markup into yunyun's markings,
+// so that the exporters can treat markdown and orgmode pages the same.
+func convertInline(text string, footnotes map[string]string) string {
+	return convertInlineWithImagePrefix(text, footnotes, "")
+}
+
+// convertInlineCell is `convertInline` that marks images as table cell images.
+func convertInlineCell(text string, footnotes map[string]string) string {
+	return convertInlineWithImagePrefix(text, footnotes, tableImagePrefix)
+}
+
+// convertInlineWithImagePrefix is `convertInline` that prefixes image links.
+func convertInlineWithImagePrefix(text string, footnotes map[string]string, imagePrefix string) string {
+	spans := &inline{protected: make([]string, 0, 4)}
+	return spans.restore(spans.convert(text, footnotes, imagePrefix))
+}
+
+// convert rewrites the markup and leaves placeholders of protected spans.
+func (spans *inline) convert(text string, footnotes map[string]string, imagePrefix string) string {
+	// Code spans go first, nothing inside of them is markup.
+	text = codeSpanRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		matches := codeSpanRegexp.FindStringSubmatch(what)
+		if len(matches[1]) != len(matches[3]) {
+			return what
+		}
+		return spans.protect(verbatim(matches[2]))
+	})
+	// Escaped characters are kept as is.
+	text = escapedRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		return spans.protect(what[1:])
+	})
+	// Footnotes are inlined, so that narumi can collect them as usual.
+	text = footnoteReferenceRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		definition, ok := footnotes[footnoteReferenceRegexp.FindStringSubmatch(what)[1]]
+		if !ok {
+			return what
+		}
+		return spans.protect("[fn:: " + spans.convert(definition, nil, "") + "]")
+	})
+	text = imageRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		matches := imageRegexp.FindStringSubmatch(what)
+		return spans.protect(yunyunLink(imagePrefix+matches[2], matches[1], matches[3]))
+	})
+	text = linkRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		matches := linkRegexp.FindStringSubmatch(what)
+		if len(matches[2]) < 1 {
+			return what
+		}
+		return spans.protect(yunyunLink(matches[2], spans.convert(matches[1], footnotes, imagePrefix), matches[3]))
+	})
+	text = autolinkRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		link := autolinkRegexp.FindStringSubmatch(what)[1]
+		return spans.protect(yunyunLink(link, link, ""))
+	})
+
+	// Emphasis, where bold delimiters are protected from italic rewrites.
+	text = strikethroughRegexp.ReplaceAllString(text, `+$1+`)
+	text = boldRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		matches := boldRegexp.FindStringSubmatch(what)
+		if len(matches[1]) > 0 {
+			return spans.protect("*") + matches[1] + spans.protect("*")
+		}
+		return matches[2] + spans.protect("*") + matches[3] + spans.protect("*") + matches[4]
+	})
+	text = italicRegexp.ReplaceAllStringFunc(text, func(what string) string {
+		matches := italicRegexp.FindStringSubmatch(what)
+		if len(matches[1]) > 0 {
+			return "/" + matches[1] + "/"
+		}
+		return matches[2] + "/" + matches[3] + "/" + matches[4]
+	})
+
+	return text
+}
+
+// orDefault returns `what` if it's not empty, `fallback` otherwise.
+func orDefault(what, fallback string) string {
+	if len(what) < 1 {
+		return fallback
+	}
+	return what
+}
+
+// verbatim wraps the code in yunyun's verbatim delimiter that is not used in it.
+func verbatim(code string) string {
+	// One leading and trailing space is allowed for code with backticks.
+	if len(code) > 2 && strings.HasPrefix(code, " ") && strings.HasSuffix(code, " ") {
+		code = code[1 : len(code)-1]
+	}
+	if strings.Contains(code, "=") && !strings.Contains(code, "~") {
+		return "~" + code + "~"
+	}
+	return "=" + code + "="
+}
+
+// yunyunLink returns the link in yunyun's `[[link][text "desc"]]` form.
+func yunyunLink(link, text, description string) string {
+	if len(text) < 1 {
+		return "[[" + link + "]]"
+	}
+	if len(description) < 1 {
+		return "[[" + link + "][" + text + "]]"
+	}
+	return "[[" + link + "][" + text + ` "` + description + `"]]`
+}
@@ -0,0 +1,57 @@
+package markdown
+
+import "testing"
+
+func Test_convertInline(t *testing.T) {
+	type args struct {
+		text      string
+		footnotes map[string]string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"Test 1", args{"plain text", nil}, "plain text"},
+		{"Test 2", args{"**bold** and *italic*", nil}, "*bold* and /italic/"},
+		{"Test 3", args{"__bold__ and _italic_", nil}, "*bold* and /italic/"},
+		{"Test 4", args{"`a*b*c` and ~~gone~~", nil}, "=a*b*c= and +gone+"},
+		{"Test 5", args{"`a = b`", nil}, "~a = b~"},
+		{"Test 6", args{"[darkness](https://example.com)", nil}, "[[https://example.com][darkness]]"},
+		{"Test 7", args{"[**bold** link](/a \"desc\")", nil}, `[[/a][*bold* link "desc"]]`},
+		{"Test 8", args{"![alt](img.png)", nil}, "[[img.png][alt]]"},
+		{"Test 9", args{"<https://example.com>", nil}, "[[https://example.com][https://example.com]]"},
+		{"Test 10", args{"note[^1]", map[string]string{"1": "*very* true"}}, "note[fn:: /very/ true]"},
+		{"Test 11", args{"snake_case_name", nil}, "snake_case_name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertInline(tt.args.text, tt.args.footnotes); got != tt.want {
+				t.Errorf("convertInline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_extractHeadingId(t *testing.T) {
+	tests := []struct {
+		name        string
+		heading     string
+		wantHeading string
+		wantId      string
+	}{
+		{"Test 1", "Heading", "Heading", ""},
+		{"Test 2", "Heading {#custom}", "Heading", "custom"},
+		{"Test 3", "Привет {#привет}", "Привет", "привет"},
+		{"Test 4", `Heading {#a"b}`, `Heading {#a"b}`, ""},
+		{"Test 5", "Heading {#a b}", "Heading {#a b}", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotHeading, gotId := extractHeadingId(tt.heading)
+			if gotHeading != tt.wantHeading || gotId != tt.wantId {
+				t.Errorf("extractHeadingId() = %v, %v, want %v, %v", gotHeading, gotId, tt.wantHeading, tt.wantId)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package markdown
+
+import (
+	"regexp"
+)
+
+const (
+	frontMatterDelimiter = "---"
+	fenceBackticks       = "```"
+	fenceTildes          = "~~~"
+	quotePrefix          = ">"
+	tablePrefix          = "|"
+	commentStart         = "<!--"
+	commentEnd           = "-->"
+	hardBreakSpaces      = "  "
+
+	// optionEnable and optionDisable are the accoutrement values
+	// that front matter booleans get translated into.
+	optionEnable  = "t"
+	optionDisable = "nil"
+
+	// imageAttribute forces the exporter to treat a link as an image.
+	imageAttribute = "image"
+	// tableImagePrefix is what the exporter expects for images in table cells.
+	tableImagePrefix = "file:"
+
+	// placeholderStart and placeholderEnd surround the index of a protected
+	// inline span while the emphasis is being rewritten.
+	placeholderStart = '\uE000'
+	placeholderEnd   = '\uE001'
+)
+
+// Front matter keys that are mapped onto page fields, everything
+// else is treated as an accoutrement option.
+const (
+	frontMatterTitle    = "title"
+	frontMatterDate     = "date"
+	frontMatterAuthor   = "author"
+	frontMatterHtmlHead = "html_head"
+	frontMatterOptions  = "options"
+	frontMatterTags     = "tags"
+	frontMatterSeries   = "series"
+)
+
+var (
+	// headingRegexp matches ATX headings, like `## Heading ##`.
+	headingRegexp = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	// headingIdRegexp matches the custom id at the end of a heading, like `{#id}`.
+	headingIdRegexp = regexp.MustCompile(`[ \t]*\{#([^\s{}]+)\}$`)
+	// setextRegexp matches setext underlines (`===` and `---`).
+	setextRegexp = regexp.MustCompile(`^(=+|-+)[ \t]*$`)
+	// horizontalLineRegexp matches thematic breaks, like `---`, `* * *`, `___`.
+	horizontalLineRegexp = regexp.MustCompile(`^(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	// listItemRegexp matches both unordered and ordered list items.
+	listItemRegexp = regexp.MustCompile(`^([ \t]*)([-*+]|\d{1,9}[.)])[ \t]+(.*)$`)
+	// tableDelimiterRegexp matches the table header delimiter row.
+	tableDelimiterRegexp = regexp.MustCompile(`^\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?$`)
+	// alertRegexp matches github-style alerts as the first line of a quote.
+	alertRegexp = regexp.MustCompile(`^\[!(NOTE|TIP|IMPORTANT|WARNING|CAUTION)\][ \t]*$`)
+	// htmlBlockRegexp matches the start of a raw html block.
+	htmlBlockRegexp = regexp.MustCompile(`(?i)^</?(address|article|aside|audio|blockquote|center|details|div|dl|figure|footer|form|h[1-6]|header|hr|iframe|nav|ol|p|picture|pre|script|section|style|svg|table|ul|video)(?:\s|/?>|$)`)
+	// footnoteDefinitionRegexp matches footnote definitions, like `[^1]: text`.
+	footnoteDefinitionRegexp = regexp.MustCompile(`^\[\^([^\]\s]+)\]:[ \t]*(.*)$`)
+	// footnoteReferenceRegexp matches footnote references, like `[^1]`.
+	footnoteReferenceRegexp = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+
+	// codeSpanRegexp matches inline code, like `code` or ``code``.
+	codeSpanRegexp = regexp.MustCompile("(`+)(.+?)(`+)")
+	// escapedRegexp matches backslash-escaped punctuation.
+	escapedRegexp = regexp.MustCompile(`\\([!"#$%&'()*+,\-./:;<=>?@\[\]^_{|}~` + "`" + `])`)
+	// imageRegexp matches inline images, like ![alt](src "title").
+	imageRegexp = regexp.MustCompile(`!\[([^\]]*)\]\(<?([^)\s>]+)>?(?:[ \t]+"([^"]*)")?\)`)
+	// linkRegexp matches inline links, like [text](href "title").
+	linkRegexp = regexp.MustCompile(`\[([^\]]+)\]\(<?([^)\s>]*)>?(?:[ \t]+"([^"]*)")?\)`)
+	// autolinkRegexp matches autolinks, like <https://example.com>.
+	autolinkRegexp = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	// boldRegexp matches **bold** and __bold__.
+	boldRegexp = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|(^|[^\pL\pN_])__(\S(?:.*?\S)?)__([^\pL\pN_]|$)`)
+	// italicRegexp matches *italic* and _italic_.
+	italicRegexp = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*|(^|[^\pL\pN_])_(\S(?:[^_]*?\S)?)_([^\pL\pN_]|$)`)
+	// strikethroughRegexp matches ~~strikethrough~~.
+	strikethroughRegexp = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	// placeholderRegexp matches protected inline spans.
+	placeholderRegexp = regexp.MustCompile(string(placeholderStart) + `(\d+)` + string(placeholderEnd))
+)
@@ -0,0 +1,99 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// frontMatterField is a single `key: value` pair from the front matter.
+type frontMatterField struct {
+	// key is the lowercase name of the field.
+	key string
+	// values are the list items, only one for scalars.
+	values []string
+}
+
+// value returns the field's values joined by spaces.
+func (f frontMatterField) value() string {
+	return strings.Join(f.values, " ")
+}
+
+// extractFrontMatter splits the yaml-like front matter from the body.
+// Only flat `key: value` and `key: [a, b]` fields are supported, with
+// block lists given as indented `- item` lines.
+func extractFrontMatter(data string) ([]frontMatterField, string) {
+	data = strings.TrimPrefix(data, "\ufeff")
+	if !strings.HasPrefix(data, frontMatterDelimiter+"\n") &&
+		!strings.HasPrefix(data, frontMatterDelimiter+"\r\n") {
+		return nil, data
+	}
+	lines := strings.Split(data, "\n")
+	fields := make([]frontMatterField, 0, len(lines))
+	for i, rawLine := range lines[1:] {
+		line := strings.TrimRight(rawLine, "\r")
+		// We have reached the end of the front matter.
+		if line == frontMatterDelimiter || line == "..." {
+			return fields, strings.Join(lines[i+2:], "\n")
+		}
+		// Skip the comments and empty lines.
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) < 1 || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		// Block list items belong to the last field.
+		if strings.HasPrefix(trimmed, "- ") && len(fields) > 0 {
+			last := &fields[len(fields)-1]
+			last.values = append(last.values, unquote(trimmed[2:]))
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		fields = append(fields, frontMatterField{
+			key:    strings.ToLower(strings.TrimSpace(key)),
+			values: frontMatterValues(strings.TrimSpace(value)),
+		})
+	}
+	// The front matter was never closed, so it wasn't one.
+	return nil, data
+}
+
+// frontMatterValues parses the value of a field, which can be a flow list.
+func frontMatterValues(value string) []string {
+	if len(value) < 1 {
+		return []string{}
+	}
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return []string{unquote(value)}
+	}
+	items := strings.Split(value[1:len(value)-1], ",")
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = unquote(strings.TrimSpace(item)); len(item) > 0 {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// unquote removes the surrounding quotes of a yaml scalar.
+func unquote(what string) string {
+	if len(what) > 1 && (what[0] == '"' || what[0] == '\'') && what[len(what)-1] == what[0] {
+		return what[1 : len(what)-1]
+	}
+	return what
+}
+
+// frontMatterOption translates the field into an accoutrement option,
+// such that `rss_title: Hello` becomes `rss-title` with `Hello`.
+func frontMatterOption(field frontMatterField) (string, string) {
+	key := strings.ReplaceAll(field.key, "_", "-")
+	switch value := field.value(); strings.ToLower(value) {
+	case "", "true", "yes", "on":
+		return key, optionEnable
+	case "false", "no", "off":
+		return key, optionDisable
+	default:
+		return key, value
+	}
+}
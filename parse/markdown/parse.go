@@ -0,0 +1,438 @@
+package markdown
+
+import (
+	"strings"
+
+	"github.com/thecsw/darkness/emilia"
+	"github.com/thecsw/darkness/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// extractFootnotes removes footnote definitions from the lines and
+// returns them by their labels, so that references can be inlined.
+func extractFootnotes(lines []string) ([]string, map[string]string) {
+	footnotes := map[string]string{}
+	kept := make([]string, 0, len(lines))
+	fence, lastLabel := "", ""
+	for _, rawLine := range lines {
+		line := strings.TrimSpace(rawLine)
+		// Don't look for definitions inside of code blocks.
+		if len(fence) > 0 {
+			if isFenceEnd(line, fence) {
+				fence = ""
+			}
+			kept = append(kept, rawLine)
+			continue
+		}
+		if fence = isFence(line); len(fence) > 0 {
+			kept = append(kept, rawLine)
+			continue
+		}
+		if matches := footnoteDefinitionRegexp.FindStringSubmatch(line); matches != nil {
+			lastLabel = matches[1]
+			footnotes[lastLabel] = matches[2]
+			continue
+		}
+		// Indented lines right after a definition continue it.
+		if len(lastLabel) > 0 && len(line) > 0 && isIndented(rawLine) {
+			footnotes[lastLabel] += " " + line
+			continue
+		}
+		lastLabel = ""
+		kept = append(kept, rawLine)
+	}
+	return kept, footnotes
+}
+
+// Do parses the input string and returns a list of elements
+func (p ParserMarkdown) Do(
+	filename yunyun.RelativePathFile,
+	data string,
+) (page *yunyun.Page, err error) {
+
+	page = yunyun.NewPage(
+		yunyun.WithFilename(filename),
+		yunyun.WithLocation(yunyun.RelativePathTrim(filename)),
+		yunyun.WithContents(make([]*yunyun.Content, 0, 32)),
+	)
+	page.Author = p.Config.RSS.DefaultAuthor
+
+	// Front matter is where markdown pages keep their options.
+	frontMatter, body := extractFrontMatter(data)
+
+	// diagnostics are the problems found while parsing the page.
+	diagnostics := yunyun.Diagnostics{}
+
+	// optionsStrings and extraOptions will get populated from the
+	// front matter and then parsed out before leaving this parser.
+	optionsStrings := ""
+	extraOptions := make([]frontMatterField, 0, len(frontMatter))
+	defer func() {
+		for _, key := range emilia.FillAccoutrement(p.Config.Website.Tombs, &optionsStrings, page) {
+			diagnostics.Warn(filename, "unknown option %q", key)
+		}
+		for _, field := range extraOptions {
+			key, value := frontMatterOption(field)
+			if !emilia.SetAccoutrementOption(key, value, page.Accoutrement) {
+				diagnostics.Warn(filename, "unknown front matter key %q", field.key)
+			}
+		}
+		err = diagnostics.Err()
+	}()
+
+	// Optional parsing to see if H.E. has been left on the first line
+	// as the date
+	defer fillHolosceneDate(page)
+
+	for _, field := range frontMatter {
+		switch field.key {
+		case frontMatterTitle:
+			page.Title = field.value()
+		case frontMatterDate:
+			page.Date = field.value()
+		case frontMatterAuthor:
+			page.Author = field.value()
+		case frontMatterHtmlHead:
+			page.HtmlHead = append(page.HtmlHead, field.values...)
+		case frontMatterOptions:
+			optionsStrings += field.value() + " "
+		case frontMatterSeries:
+			page.Series = field.value()
+		case frontMatterTags:
+			for _, value := range field.values {
+				page.AddTags(strings.Split(value, ",")...)
+			}
+		default:
+			extraOptions = append(extraOptions, field)
+		}
+	}
+
+	// Markdown is rewritten into yunyun's default markings, which
+	// the exporters expect to be built
+	yunyun.ActiveMarkings.BuildRegex()
+
+	// Split the data into lines, where the padded empty line makes sure
+	// that last elements are processed before an EOF
+	lines, footnotes := extractFootnotes(strings.Split(body+"\n", "\n"))
+
+	// currentFlags uses flags to set options
+	currentFlags := yunyun.Bits(0)
+	addFlag, removeFlag, _, hasFlag := yunyun.LatchFlags(&currentFlags)
+
+	// paragraph is the lines of the current paragraph
+	paragraph := make([]string, 0, 8)
+	// quote is the lines of the current block quote, where empty
+	// strings separate the paragraphs
+	quote := make([]string, 0, 8)
+	// attentionTitle is set if the block quote is a github alert
+	attentionTitle := ""
+	// list is the items of the current list
+	list := make([]yunyun.ListItem, 0, 8)
+	// listNumbered is true if the current list is ordered
+	listNumbered := false
+	// listIndents is the stack of indents for nested list items
+	listIndents := make([]int, 0, 4)
+	// listSawEmptyLine is true if an empty line was met within a list
+	listSawEmptyLine := false
+	// table is the rows of the current table
+	table := make([][]string, 0, 8)
+	// sourceCode is the lines of the current fenced code block
+	sourceCode := make([]string, 0, 16)
+	// sourceCodeFence is the fence that opened the code block
+	sourceCodeFence := ""
+	// sourceCodeLang is the language of the source code block
+	sourceCodeLang := ""
+	// rawHtml is the lines of the current raw html block
+	rawHtml := make([]string, 0, 8)
+	// inComment is true inside of a multi-line html comment
+	inComment := false
+
+	// addContent is a helper function to add content to the page
+	addContent := func(content *yunyun.Content) {
+		page.Contents = append(page.Contents, content)
+	}
+
+	// flush ends whatever block we are currently reading
+	flush := func() {
+		switch {
+		case hasFlag(yunyun.InListFlag):
+			contentType := yunyun.TypeList
+			if listNumbered {
+				contentType = yunyun.TypeListNumbered
+			}
+			for i := range list {
+				list[i].Text = convertInline(list[i].Text, footnotes)
+			}
+			addContent(&yunyun.Content{
+				Type: contentType,
+				List: list,
+			})
+			list = make([]yunyun.ListItem, 0, 8)
+			listIndents = listIndents[:0]
+			listSawEmptyLine = false
+			removeFlag(yunyun.InListFlag)
+		case hasFlag(yunyun.InTableFlag):
+			addContent(&yunyun.Content{
+				Type:         yunyun.TypeTable,
+				Table:        table,
+				TableHeaders: hasFlag(yunyun.InTableHasHeadersFlag),
+			})
+			table = make([][]string, 0, 8)
+			removeFlag(yunyun.InTableFlag | yunyun.InTableHasHeadersFlag)
+		case hasFlag(yunyun.InQuoteFlag):
+			// Github alerts are our attention blocks
+			if len(attentionTitle) > 0 {
+				addContent(&yunyun.Content{
+					Type:           yunyun.TypeAttentionText,
+					AttentionTitle: attentionTitle,
+					AttentionText:  convertInline(joinLines(gana.Filter(isNotEmpty, quote)), footnotes),
+				})
+			}
+			// Otherwise, every quote paragraph is its own content
+			for len(attentionTitle) < 1 && len(quote) > 0 {
+				end := len(quote)
+				for i, line := range quote {
+					if len(line) < 1 {
+						end = i
+						break
+					}
+				}
+				if end > 0 {
+					addContent(formParagraph(joinLines(quote[:end]), footnotes, yunyun.InQuoteFlag))
+				}
+				quote = quote[gana.Min(end+1, len(quote)):]
+			}
+			quote = make([]string, 0, 8)
+			attentionTitle = ""
+			removeFlag(yunyun.InQuoteFlag)
+		case len(paragraph) > 0:
+			text := joinLines(paragraph)
+			paragraph = make([]string, 0, 8)
+			// Let's see if our context is a standalone link
+			if link := getLink(text, footnotes); link != nil {
+				addContent(link)
+				return
+			}
+			// By default, save whatever we have as a paragraph
+			addContent(formParagraph(text, footnotes, 0))
+		}
+	}
+
+	// Loop through the lines
+	for _, rawLine := range lines {
+		// Trim the line from whitespaces
+		line := strings.TrimSpace(rawLine)
+
+		// If we are in a source code block?
+		if hasFlag(yunyun.InSourceCodeFlag) {
+			// Check if it's time to leave
+			if isFenceEnd(line, sourceCodeFence) {
+				removeFlag(yunyun.InSourceCodeFlag)
+				addContent(&yunyun.Content{
+					Type:           yunyun.TypeSourceCode,
+					SourceCodeLang: sourceCodeLang,
+					SourceCode:     strings.TrimRight(strings.Join(sourceCode, "\n"), "\n\t\r\f\b"),
+				})
+				sourceCode = make([]string, 0, 16)
+				continue
+			}
+			sourceCode = append(sourceCode, strings.TrimRight(rawLine, "\r"))
+			continue
+		}
+		// If we are in a raw html environment, an empty line ends it
+		if hasFlag(yunyun.InRawHtmlFlag) {
+			if len(line) > 0 {
+				rawHtml = append(rawHtml, rawLine)
+				continue
+			}
+			removeFlag(yunyun.InRawHtmlFlag)
+			addContent(&yunyun.Content{
+				Type:    yunyun.TypeRawHtml,
+				RawHtml: strings.Join(rawHtml, "\n"),
+				Options: yunyun.InRawHtmlFlag | yunyun.InRawHtmlFlagUnsafe,
+			})
+			rawHtml = make([]string, 0, 8)
+			continue
+		}
+		// Skip html comments, which can span multiple lines
+		if inComment {
+			inComment = !strings.Contains(line, commentEnd)
+			continue
+		}
+		if isComment(line) {
+			continue
+		}
+		if strings.HasPrefix(line, commentStart) {
+			inComment = true
+			continue
+		}
+		// If we hit an empty line, end the whatever block we had, except
+		// for lists, which can have empty lines between items
+		if line == "" {
+			if hasFlag(yunyun.InListFlag) {
+				listSawEmptyLine = true
+				continue
+			}
+			flush()
+			continue
+		}
+		item := isList(rawLine)
+		// The list is over if the empty line is not followed by its item
+		// or an indented continuation
+		if hasFlag(yunyun.InListFlag) && listSawEmptyLine && item == nil && !isIndented(rawLine) {
+			flush()
+		}
+		// Should we enter a source code environment?
+		if fence := isFence(line); len(fence) > 0 {
+			flush()
+			sourceCodeFence = fence
+			sourceCodeLang = extractSourceCodeLanguage(line, fence)
+			addFlag(yunyun.InSourceCodeFlag)
+			continue
+		}
+		// Raw html can't interrupt a paragraph
+		if len(paragraph) < 1 && !hasFlag(yunyun.InListFlag) && isHtmlBlock(line) {
+			flush()
+			addFlag(yunyun.InRawHtmlFlag)
+			rawHtml = append(rawHtml, rawLine)
+			continue
+		}
+		// Now, we need to parse headings here
+		if header := isHeader(line, footnotes); header != nil {
+			flush()
+			if header.HeadingLevel == 1 {
+				page.Title = header.Heading
+				continue
+			}
+			addContent(header)
+			continue
+		}
+		// Setext headings underline the paragraph we've been reading
+		if level := isSetextUnderline(line); level > 0 && len(paragraph) > 0 &&
+			!hasFlag(yunyun.InListFlag|yunyun.InQuoteFlag|yunyun.InTableFlag) {
+			text, id := extractHeadingId(joinLines(paragraph))
+			heading := convertInline(text, footnotes)
+			paragraph = make([]string, 0, 8)
+			if level == 1 {
+				page.Title = heading
+				continue
+			}
+			addContent(&yunyun.Content{
+				Type:         yunyun.TypeHeading,
+				HeadingLevel: level,
+				Heading:      heading,
+				HeadingId:    id,
+			})
+			continue
+		}
+		// Add a horizontal line divider
+		if isHorizonalLine(line) {
+			flush()
+			addContent(&yunyun.Content{
+				Type: yunyun.TypeHorizontalLine,
+			})
+			continue
+		}
+		// Tables are rows of pipes
+		if isTable(line) {
+			if !hasFlag(yunyun.InTableFlag) {
+				flush()
+				addFlag(yunyun.InTableFlag)
+			}
+			// The delimiter after the first row makes it the header
+			if isTableHeaderDelimeter(line) {
+				if len(table) == 1 {
+					addFlag(yunyun.InTableHasHeadersFlag)
+				}
+				continue
+			}
+			table = append(table, extractTableRow(line, footnotes))
+			continue
+		}
+		if hasFlag(yunyun.InTableFlag) {
+			flush()
+		}
+		// Block quotes, where the first line can make it an alert
+		if isQuote(line) {
+			if !hasFlag(yunyun.InQuoteFlag) {
+				flush()
+				addFlag(yunyun.InQuoteFlag)
+				if matches := alertRegexp.FindStringSubmatch(extractQuote(line)); matches != nil {
+					attentionTitle = matches[1]
+					continue
+				}
+			}
+			quote = append(quote, strings.TrimSpace(extractQuote(line)))
+			continue
+		}
+		// List items start new lists or continue the current one
+		if item != nil {
+			if !hasFlag(yunyun.InListFlag) {
+				flush()
+				addFlag(yunyun.InListFlag)
+				listNumbered = item.numbered
+			}
+			// Pop the indents until we find our parent
+			for len(listIndents) > 0 && item.indent < gana.Last(listIndents) {
+				listIndents = listIndents[:len(listIndents)-1]
+			}
+			if len(listIndents) < 1 || item.indent > gana.Last(listIndents) {
+				listIndents = append(listIndents, item.indent)
+			}
+			list = append(list, yunyun.ListItem{
+				Level: uint8(len(listIndents)),
+				Text:  item.text,
+			})
+			listSawEmptyLine = false
+			continue
+		}
+		// Continuation of the last list item
+		if hasFlag(yunyun.InListFlag) && len(list) > 0 {
+			list[len(list)-1].Text += " " + line
+			listSawEmptyLine = false
+			continue
+		}
+		// Lazy continuation of the block quote
+		if hasFlag(yunyun.InQuoteFlag) {
+			quote = append(quote, line)
+			continue
+		}
+		paragraph = append(paragraph, strings.TrimLeft(rawLine, " \t"))
+	}
+
+	// Code blocks that were never closed run until the end
+	if hasFlag(yunyun.InSourceCodeFlag) {
+		addContent(&yunyun.Content{
+			Type:           yunyun.TypeSourceCode,
+			SourceCodeLang: sourceCodeLang,
+			SourceCode:     strings.TrimRight(strings.Join(sourceCode, "\n"), "\n\t\r\f\b"),
+		})
+	}
+	flush()
+
+	return page, nil
+}
+
+// isNotEmpty returns true if the string is not empty.
+func isNotEmpty(what string) bool {
+	return len(what) > 0
+}
+
+// fillHolosceneDate tries to find a date in the format of "H.E." and
+// saves it as the page's date.
+func fillHolosceneDate(page *yunyun.Page) {
+	// No contents found?
+	if len(page.Contents) < 1 {
+		return
+	}
+	first := gana.First(page.Contents)
+	// Needs to be a simple text
+	if !first.IsParagraph() {
+		return
+	}
+	if !strings.HasSuffix(first.Paragraph, "H.E.") {
+		return
+	}
+	page.Date = first.Paragraph
+	page.DateHoloscene = true
+}
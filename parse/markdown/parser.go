@@ -0,0 +1,11 @@
+package markdown
+
+import (
+	"github.com/thecsw/darkness/emilia/alpha"
+)
+
+// ParserMarkdown is the parser for markdown files.
+type ParserMarkdown struct {
+	// Config is the configuration for the parser.
+	Config *alpha.DarknessConfig
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/thecsw/darkness/emilia/puck"
 	"github.com/thecsw/darkness/yunyun"
@@ -201,6 +202,27 @@ func extractAuthor(line string) string {
 	return extractOptionLabel(line, optionAuthor)
 }
 
+// extractSeries extracts series `SERIES` from `#+series: SERIES`.
+func extractSeries(line string) string {
+	return extractOptionLabel(line, optionSeries)
+}
+
+// extractCustomId extracts `ID` from `:CUSTOM_ID: ID` in a property drawer.
+func extractCustomId(line string) (string, bool) {
+	if len(line) < len(propertyCustomId) || !strings.EqualFold(line[:len(propertyCustomId)], propertyCustomId) {
+		return "", false
+	}
+	id := strings.TrimSpace(line[len(propertyCustomId):])
+	return id, len(id) > 0
+}
+
+// extractTags extracts tags `A`, `B` from `#+filetags: :A:B:` or `#+tags: A B`.
+func extractTags(line string, option string) []string {
+	return strings.FieldsFunc(extractOptionLabel(line, option), func(r rune) bool {
+		return r == ':' || unicode.IsSpace(r)
+	})
+}
+
 // extractGalleryFolder extracts gallery `FOLDER` from `#+begin_gallery FOLDER`.
 func extractGalleryFolder(line string) string {
 	path, err := extractCustomBlockOption(line, `path`, regexpPatternNoWhitespace)
@@ -29,8 +29,15 @@ const (
 	optionHtmlTags     = "html_tags:"
 	optionAttrHtml     = "attr_html:"
 	optionAuthor       = "author:"
+	optionFileTags     = "filetags:"
+	optionTags         = "tags:"
+	optionSeries       = "series:"
 	horizontalLine     = "-----"
 
+	drawerProperties = ":PROPERTIES:"
+	drawerEnd        = ":END:"
+	propertyCustomId = ":CUSTOM_ID:"
+
 	sectionLevelOne   = "* "
 	sectionLevelTwo   = "** "
 	sectionLevelThree = "*** "
@@ -50,6 +57,13 @@ var (
 		optionBeginDetails, optionEndDetails,
 		optionBeginGallery, optionEndGallery,
 	}
+	// blockEndings maps the endings of the blocks to their beginnings.
+	blockEndings = map[string]string{
+		optionEndQuote:   optionBeginQuote,
+		optionEndCenter:  optionBeginCenter,
+		optionEndDetails: optionBeginDetails,
+		optionEndGallery: optionBeginGallery,
+	}
 	// linkRegexp is the regexp for matching links
 	linkRegexp *regexp.Regexp
 	// attentionBlockRegexp is the regexp for matching attention blocks
@@ -1,6 +1,7 @@
 package orgmode
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/thecsw/darkness/emilia"
@@ -8,21 +9,42 @@ import (
 	"github.com/thecsw/gana"
 )
 
-// preprocess preprocesses the input string to be parser-friendly
-func preprocess(data string) string {
-	// Add a newline before every heading just in case if
-	// there is no terminating empty line before each one
-	data = headingRegexp.ReplaceAllString(data, "\n$1")
-	// Center and quote delimeters need a new line around
-	for _, v := range surroundWithNewlines {
-		data = strings.ReplaceAll(data,
-			optionPrefix+v,
-			"\n"+optionPrefix+v)
+// preprocess preprocesses the input string to be parser-friendly and
+// returns its lines with the source positions they start at
+func preprocess(data string) ([]string, []position) {
+	sourceLines := strings.Split(data, "\n")
+	lines := make([]string, 0, len(sourceLines)+1)
+	positions := make([]position, 0, len(sourceLines)+1)
+	for i, line := range sourceLines {
+		// Add a newline before every heading just in case if
+		// there is no terminating empty line before each one
+		line = headingRegexp.ReplaceAllString(line, "\n$1")
+		// Center and quote delimeters need a new line around
+		for _, v := range surroundWithNewlines {
+			line = strings.ReplaceAll(line,
+				optionPrefix+v,
+				"\n"+optionPrefix+v)
+		}
+		// Only newlines were added, so the split lines follow each
+		// other in the source line
+		column := 1
+		for _, split := range strings.Split(line, "\n") {
+			lines = append(lines, split)
+			positions = append(positions, position{i + 1, column})
+			column += len(split)
+		}
 	}
 	// Pad a newline so that last elements can be processed
 	// properly before an EOF is encountered during parsing
-	data += "\n"
-	return data
+	lines = append(lines, "")
+	positions = append(positions, position{len(sourceLines) + 1, 1})
+	return lines, positions
+}
+
+// position is a line and a column in the source, starting at 1.
+type position struct {
+	line   int
+	column int
 }
 
 const (
@@ -33,12 +55,12 @@ const (
 func (p ParserOrgmode) Do(
 	filename yunyun.RelativePathFile,
 	data string,
-) *yunyun.Page {
+) (page *yunyun.Page, err error) {
 
 	// Split the data into lines
-	lines := strings.Split(preprocess(data), "\n")
+	lines, positions := preprocess(data)
 
-	page := yunyun.NewPage(
+	page = yunyun.NewPage(
 		yunyun.WithFilename(filename),
 		yunyun.WithLocation(yunyun.RelativePathTrim(filename)),
 		yunyun.WithContents(make([]*yunyun.Content, 0, 32)),
@@ -66,10 +88,42 @@ func (p ParserOrgmode) Do(
 	// listItemInitialIndent is the initial indent of the list item
 	listItemInitialIndent := uint8(0)
 
+	// diagnostics are the problems found while parsing the page.
+	diagnostics := yunyun.Diagnostics{}
+	// current is the position of the line being parsed
+	current := position{}
+	// sourceCodeStart is where the current source code block began
+	sourceCodeStart := position{}
+	// rawHtmlStart is where the current raw html block began
+	rawHtmlStart := position{}
+	// openedBlocks are where the currently opened blocks began
+	openedBlocks := map[string]position{}
+	// tableRows are the positions of the current table's rows
+	tableRows := make([]position, 0, 8)
+	// drawerStart is where the current property drawer began, if we're in one
+	drawerStart := position{}
+	// lastHeading is the heading right before, which gets the property drawer
+	var lastHeading *yunyun.Content
+
 	// optionsStrings will get populated as the page is being scanned
 	// and then parsed out before leaving this parser.
 	optionsStrings := ""
-	defer emilia.FillAccoutrement(p.Config.Website.Tombs, &optionsStrings, page)
+	// optionsPositions are where the options keys were first given.
+	optionsPositions := map[string]position{}
+	defer func() {
+		reported := map[string]bool{}
+		for _, key := range emilia.FillAccoutrement(p.Config.Website.Tombs, &optionsStrings, page) {
+			// Options given many times are reported where they first were.
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+			where := optionsPositions[key]
+			diagnostics.WarnAt(filename, where.line, where.column, "unknown option %q", key)
+		}
+		diagnostics.Sort()
+		err = diagnostics.Err()
+	}()
 
 	// Optional parsing to see if H.E. has been left on the first line
 	// as the date
@@ -86,6 +140,7 @@ func (p ParserOrgmode) Do(
 		content.Attributes = attributes
 		content.CustomHtmlTags = customHtmlTags
 		page.Contents = append(page.Contents, content)
+		lastHeading = nil
 		currentContext = ""
 		galleryPath = ""
 		galleryWidth = defaultGalleryImagesPerRow
@@ -120,10 +175,22 @@ func (p ParserOrgmode) Do(
 		optionCaption:    func(line string) { caption = extractCaptionTitle(line) },
 		optionDate:       func(line string) { page.Date = extractDate(line) },
 		optionHtmlHead:   func(line string) { page.HtmlHead = append(page.HtmlHead, extractHtmlHead(line)) },
-		optionOptions:    func(line string) { optionsStrings += extractOptions(line) + " " },
+		optionOptions: func(line string) {
+			options := extractOptions(line)
+			for _, option := range strings.Fields(options) {
+				key, _, _ := strings.Cut(option, ":")
+				if _, ok := optionsPositions[key]; !ok {
+					optionsPositions[key] = position{current.line, strings.Index(line, option) + 1}
+				}
+			}
+			optionsStrings += options + " "
+		},
 		optionAttributes: func(line string) { attributes = extractAttributes(line) },
 		optionAuthor:     func(line string) { page.Author = extractAuthor(line) },
 		optionHtmlTags:   func(line string) { customHtmlTags = extractHtmlTags(line) },
+		optionFileTags:   func(line string) { page.AddTags(extractTags(line, optionFileTags)...) },
+		optionTags:       func(line string) { page.AddTags(extractTags(line, optionTags)...) },
+		optionSeries:     func(line string) { page.Series = extractSeries(line) },
 	}
 
 	// Yunyun's markings default to orgmode
@@ -131,9 +198,11 @@ func (p ParserOrgmode) Do(
 	linkRegexp = yunyun.LinkRegexp
 
 	// Loop through the lines
-	for _, rawLine := range lines {
+	for i, rawLine := range lines {
 		// Trimp the line from whitespaces
 		line := strings.TrimSpace(rawLine)
+		// Remember where we are for the diagnostics
+		current = position{positions[i].line, positions[i].column + len(rawLine) - len(strings.TrimLeft(rawLine, " \t"))}
 		// Save the previous state and update the current
 		// one with the newly read line
 		previousContext := currentContext
@@ -159,6 +228,7 @@ func (p ParserOrgmode) Do(
 		// Now, check if we can enter a raw html environment
 		if isHtmlExportBegin(line) {
 			addFlag(yunyun.InRawHtmlFlag)
+			rawHtmlStart = current
 			if strings.Contains(line, "unsafe") {
 				addFlag(yunyun.InRawHtmlFlagUnsafe)
 			} else if strings.Contains(line, "responsive") || strings.Contains(line, "iframe") {
@@ -190,9 +260,31 @@ func (p ParserOrgmode) Do(
 		if isSourceCodeBegin(line) {
 			sourceCodeLang = extractSourceCodeLanguage(line)
 			addFlag(yunyun.InSourceCodeFlag)
+			sourceCodeStart = current
 			currentContext = ""
 			continue
 		}
+		// Property drawers are not exported, only the heading's custom id
+		// is kept, which becomes the heading's anchor
+		if strings.EqualFold(line, drawerProperties) {
+			drawerStart = current
+			currentContext = previousContext
+			continue
+		}
+		if drawerStart.line > 0 {
+			if strings.EqualFold(line, drawerEnd) {
+				drawerStart = position{}
+			} else if id, ok := extractCustomId(line); ok && lastHeading != nil {
+				if yunyun.IsValidHeadingId(id) {
+					lastHeading.HeadingId = id
+				} else {
+					diagnostics.WarnAt(filename, current.line, current.column,
+						"custom id %q can't have spaces or quotes, using the heading's text instead", id)
+				}
+			}
+			currentContext = previousContext
+			continue
+		}
 		// Ignore orgmode comments and options, where source code blocks
 		// and export block options are exceptions to this rule
 		if isComment(line) {
@@ -208,6 +300,16 @@ func (p ParserOrgmode) Do(
 				continue
 			}
 			option := optionAndValue[0]
+			// Blocks should be opened before they are closed
+			if beginning, ok := blockEndings[option]; ok {
+				if _, opened := openedBlocks[beginning]; !opened {
+					diagnostics.WarnAt(filename, current.line, current.column,
+						"%s%s without a matching %s%s", optionPrefix, option, optionPrefix, beginning)
+				}
+				delete(openedBlocks, beginning)
+			} else if slices.Contains(surroundWithNewlines, option) {
+				openedBlocks[option] = current
+			}
 			if action, ok := optionsActions[option]; ok {
 				action(rawLine)
 			}
@@ -222,6 +324,7 @@ func (p ParserOrgmode) Do(
 				continue
 			}
 			addContent(header)
+			lastHeading = header
 			continue
 		}
 		// If we hit an empty line, end the whatever context we had
@@ -277,6 +380,7 @@ func (p ParserOrgmode) Do(
 				// the first item is a vertical bar, so we skip it
 				rows := splitItems[1:]
 				tableData := make([][]string, len(rows))
+				tableColumns := -1
 				for i, row := range rows {
 					row = strings.TrimSpace(row)
 					if len(row) < 1 {
@@ -291,7 +395,15 @@ func (p ParserOrgmode) Do(
 						columns[j] = strings.TrimSpace(item)
 					}
 					tableData[i] = columns
+					// All the rows should have as many columns as the first one
+					if tableColumns < 0 {
+						tableColumns = len(columns)
+					} else if len(columns) != tableColumns && i < len(tableRows) {
+						diagnostics.WarnAt(filename, tableRows[i].line, tableRows[i].column,
+							"table row has %d columns instead of %d", len(columns), tableColumns)
+					}
 				}
+				tableRows = tableRows[:0]
 				addContent(&yunyun.Content{
 					Type:         yunyun.TypeTable,
 					Table:        tableData,
@@ -332,11 +444,30 @@ func (p ParserOrgmode) Do(
 				continue
 			}
 			currentContext = previousContext + tableSeparatorWS + line
+			tableRows = append(tableRows, current)
 		}
 		currentContext += " "
 	}
 
-	return page
+	// Blocks that were never closed lose their contents
+	if hasFlag(yunyun.InSourceCodeFlag) {
+		diagnostics.WarnAt(filename, sourceCodeStart.line, sourceCodeStart.column,
+			"%s%s is never closed with %s%s", optionPrefix, optionBeginSource, optionPrefix, optionEndSource)
+	}
+	if hasFlag(yunyun.InRawHtmlFlag) {
+		diagnostics.WarnAt(filename, rawHtmlStart.line, rawHtmlStart.column,
+			"%s%s is never closed with %s%s", optionPrefix, optionBeginExport, optionPrefix, optionEndExport)
+	}
+	if drawerStart.line > 0 {
+		diagnostics.WarnAt(filename, drawerStart.line, drawerStart.column,
+			"%s is never closed with %s", drawerProperties, drawerEnd)
+	}
+	for beginning, where := range openedBlocks {
+		diagnostics.WarnAt(filename, where.line, where.column,
+			"%s%s is never closed", optionPrefix, beginning)
+	}
+
+	return page, nil
 }
 
 // fillHolosceneDate tries to find a date in the format of "H.E." and
@@ -0,0 +1,71 @@
+package orgmode
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/thecsw/darkness/emilia/alpha"
+	"github.com/thecsw/darkness/yunyun"
+)
+
+func TestDiagnosticPositions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"Unclosed source code", "#+title: T\n\ntext\n  #+begin_src go\nfmt.Println()\n", []string{
+			"page.org:4:3: warning: #+begin_src is never closed with #+end_src",
+		}},
+		{"Unclosed export", "#+begin_export html\n<b>bold</b>\n", []string{
+			"page.org:1:1: warning: #+begin_export is never closed with #+end_export",
+		}},
+		{"Unclosed quote", "one\n\n#+begin_quote\nquoted\n", []string{
+			"page.org:3:1: warning: #+begin_quote is never closed",
+		}},
+		{"Unclosed drawer", "** Heading\n:PROPERTIES:\n:CUSTOM_ID: x\n", []string{
+			"page.org:2:1: warning: :PROPERTIES: is never closed with :END:",
+		}},
+		{"Stray end", "one\n\n  #+end_center\n", []string{
+			"page.org:3:3: warning: #+end_center without a matching #+begin_center",
+		}},
+		{"Stray end after the text", "one #+end_quote\n", []string{
+			"page.org:1:5: warning: #+end_quote without a matching #+begin_quote",
+		}},
+		{"Table columns", "| a | b |\n|---+---|\n| c |\n| d | e |\n| f | g | h |\n", []string{
+			"page.org:3:1: warning: table row has 1 columns instead of 2",
+			"page.org:5:1: warning: table row has 3 columns instead of 2",
+		}},
+		{"Unknown options", "#+title: T\n#+options: toc:nil bogus:t\n#+options: other:t bogus:nil\n", []string{
+			"page.org:2:20: warning: unknown option \"bogus\"",
+			"page.org:3:12: warning: unknown option \"other\"",
+		}},
+		{"Macros", "#+macro: hi Hello $1\n{{{hi(world)}}} and {{{hi(you)}}}\n\n#+begin_details {{{hi(x)}}}\n", []string{
+			"page.org:4:1: warning: #+begin_details is never closed",
+		}},
+		{"Continuation lines", "first line \\\\\nsecond line \\\\\nthird line\n| a | b |\n| c |\n", []string{
+			"page.org:5:1: warning: table row has 1 columns instead of 2",
+		}},
+		{"Heading in the middle of a line", "text * Heading\n\n#+begin_gallery\n", []string{
+			"page.org:3:1: warning: #+begin_gallery is never closed",
+		}},
+	}
+	conf := &alpha.DarknessConfig{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParserOrgmode{Config: conf}.Do("page.org", tt.input)
+			diagnostics := yunyun.Diagnostics{}
+			if err != nil && !errors.As(err, &diagnostics) {
+				t.Fatalf("Do() error = %v, want diagnostics", err)
+			}
+			got := make([]string, 0, len(diagnostics))
+			for _, diagnostic := range diagnostics {
+				got = append(got, diagnostic.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Do() diagnostics =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -2,27 +2,50 @@ package parse
 
 import (
 	"log"
+	"path/filepath"
 
 	"github.com/thecsw/darkness/emilia/alpha"
 	"github.com/thecsw/darkness/emilia/puck"
+	"github.com/thecsw/darkness/parse/markdown"
 	"github.com/thecsw/darkness/parse/orgmode"
 	"github.com/thecsw/darkness/yunyun"
 )
 
 // Parser is the interface for all parsers.
 type Parser interface {
-	// Do parses the file and returns a Page.
-	Do(yunyun.RelativePathFile, string) *yunyun.Page
+	// Do parses the file and returns a Page. The error can be
+	// `yunyun.Diagnostics`, where the page is still usable if
+	// there are only warnings.
+	Do(yunyun.RelativePathFile, string) (*yunyun.Page, error)
 }
 
-// BuildParser builds a parser based on the config.
-func BuildParser(conf *alpha.DarknessConfig) Parser {
+// Parsers maps input extensions to their parsers.
+type Parsers map[string]Parser
+
+// For returns the parser registered for the file's extension, nil if none.
+func (p Parsers) For(filename yunyun.FullPathFile) Parser {
+	return p[filepath.Ext(string(filename))]
+}
+
+// BuildParsers builds a parser for every input format in the config.
+func BuildParsers(conf *alpha.DarknessConfig) Parsers {
+	parsers := make(Parsers, len(conf.Project.Input))
+	for _, ext := range conf.Project.Input {
+		parsers[ext] = BuildParser(conf, ext)
+	}
+	return parsers
+}
+
+// BuildParser builds a parser for the given input format.
+func BuildParser(conf *alpha.DarknessConfig, ext string) Parser {
 	var parser Parser
-	switch conf.Project.Input {
+	switch ext {
 	case puck.ExtensionOrgmode: // orgmode
 		parser = orgmode.ParserOrgmode{Config: conf}
+	case puck.ExtensionMarkdown: // markdown
+		parser = markdown.ParserMarkdown{Config: conf}
 	default: // unknown
-		log.Fatalf("unknown input format: %s", conf.Project.Input)
+		log.Fatalf("unknown input format: %s", ext)
 	}
 	return parser
 }
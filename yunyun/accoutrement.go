@@ -32,6 +32,15 @@ type Accoutrement struct {
 	RssPrefix string
 	// RssTitle is the title of the page in the rss feed. Still prefixed with RssPrefix.
 	RssTitle string
+	// Enclosure is the media file attached to the page in the rss feed, the
+	// first audio or video link on the page is used if it's empty.
+	Enclosure string
+	// EnclosureDuration is the duration of the enclosure in seconds.
+	EnclosureDuration string
+	// Episode is the podcast episode number of the page.
+	Episode string
+	// Explicit marks the page's enclosure as explicit for podcast apps.
+	Explicit AccoutrementFlip
 }
 
 // ExcludeHtmlHeadContains is a type to store excluded keywords for html head.
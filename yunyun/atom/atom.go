@@ -0,0 +1,140 @@
+package atom
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	// Namespace is the namespace of the atom syndication format.
+	Namespace = "http://www.w3.org/2005/Atom"
+
+	// AtomFormat is the date format used in the atom spec.
+	AtomFormat = time.RFC3339
+
+	// AtomDocs is the atom spec implemented.
+	AtomDocs = "https://www.rfc-editor.org/rfc/rfc4287"
+)
+
+// Feed is the <feed> root of an atom document, which holds the
+// metadata of the feed and its entries.
+type Feed struct {
+	XMLName xml.Name `xml:"feed"`
+
+	// Namespace is the atom namespace, always `Namespace`.
+	Namespace string `xml:"xmlns,attr"`
+
+	// ID is a permanent, universally unique identifier of the feed,
+	// usually the website's url.
+	ID string `xml:"id"`
+
+	// Title is a human-readable title of the feed.
+	Title *Text `xml:"title"`
+
+	// Subtitle is a human-readable description of the feed (optional).
+	Subtitle *Text `xml:"subtitle,omitempty"`
+
+	// Updated is the last time the feed was modified in a significant way.
+	Updated string `xml:"updated"`
+
+	// Links are the related web pages, usually the website with
+	// rel="alternate" and the feed itself with rel="self".
+	Links []Link `xml:"link"`
+
+	// Author is the author of the feed, required unless all
+	// entries have their authors (optional).
+	Author *Person `xml:"author,omitempty"`
+
+	// Rights conveys information about rights held in and over the feed (optional).
+	Rights string `xml:"rights,omitempty"`
+
+	// Generator identifies the software used to generate the feed (optional).
+	Generator string `xml:"generator,omitempty"`
+
+	// Entries are the entries of the feed.
+	Entries []Entry `xml:"entry"`
+}
+
+// Entry is an <entry> of the feed, a single page.
+type Entry struct {
+	XMLName xml.Name `xml:"entry"`
+
+	// ID is a permanent, universally unique identifier of the entry.
+	ID string `xml:"id"`
+
+	// Title is a human-readable title of the entry.
+	Title *Text `xml:"title"`
+
+	// Updated is the last time the entry was modified in a significant way.
+	Updated string `xml:"updated"`
+
+	// Published is the time of the initial creation of the entry (optional).
+	Published string `xml:"published,omitempty"`
+
+	// Links are the related web pages, the page itself with rel="alternate".
+	Links []Link `xml:"link"`
+
+	// Author is the author of the entry (optional).
+	Author *Person `xml:"author,omitempty"`
+
+	// Categories are the categories of the entry (optional).
+	Categories []Category `xml:"category"`
+
+	// Summary is a short summary or an excerpt of the entry (optional).
+	Summary *Text `xml:"summary,omitempty"`
+
+	// Content is the content of the entry (optional).
+	Content *Text `xml:"content,omitempty"`
+}
+
+// Text is a human-readable text, which can be plain text or html.
+type Text struct {
+	// Type is either "text" or "html".
+	Type string `xml:"type,attr,omitempty"`
+
+	// Value is the text itself.
+	Value string `xml:",chardata"`
+}
+
+// Link is a <link> to a related web page.
+type Link struct {
+	XMLName xml.Name `xml:"link"`
+
+	// Href is the url of the page.
+	Href string `xml:"href,attr"`
+
+	// Rel is the relation type of the link, "alternate" if omitted.
+	Rel string `xml:"rel,attr,omitempty"`
+
+	// Type is the media type of the page (optional).
+	Type string `xml:"type,attr,omitempty"`
+
+	// Length is the size of the linked file in bytes (optional).
+	Length int64 `xml:"length,attr,omitempty"`
+}
+
+// Person is a person, corporation, or a similar entity.
+type Person struct {
+	// Name is a human-readable name of the person.
+	Name string `xml:"name"`
+
+	// Uri is the home page of the person (optional).
+	Uri string `xml:"uri,omitempty"`
+
+	// Email is the email address of the person (optional).
+	Email string `xml:"email,omitempty"`
+}
+
+// Category is a <category> of the entry.
+type Category struct {
+	XMLName xml.Name `xml:"category"`
+
+	// Term is the category itself.
+	Term string `xml:"term,attr"`
+
+	// Scheme identifies the categorization scheme, like the category's url (optional).
+	Scheme string `xml:"scheme,attr,omitempty"`
+
+	// Label is a human-readable label of the category (optional).
+	Label string `xml:"label,attr,omitempty"`
+}
@@ -1,6 +1,9 @@
 package yunyun
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/thecsw/gana"
 )
 
@@ -43,6 +46,10 @@ type Content struct {
 	// Heading is the heading text.
 	Heading string
 
+	// HeadingId is the heading's anchor id given by the user, like
+	// orgmode's `:CUSTOM_ID:`, the anchor comes from the text if empty.
+	HeadingId string
+
 	// Paragraph is the paragraph text.
 	Paragraph string
 
@@ -114,6 +121,14 @@ func (c Contents) Headings() Contents {
 	return gana.Filter(func(v *Content) bool { return v.IsHeading() }, c)
 }
 
+// IsValidHeadingId tells us if the custom heading id can be used as an
+// html id and a link's fragment, so it has no spaces, quotes, or alike.
+func IsValidHeadingId(id string) bool {
+	return len(id) > 0 && !strings.ContainsFunc(id, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`"'<>&#`, r)
+	})
+}
+
 // SourceCodeBlocks returns all source code blocks from contents.
 func (c Contents) SourceCodeBlocks() Contents {
 	return gana.Filter(func(v *Content) bool { return v.IsSourceCode() }, c)
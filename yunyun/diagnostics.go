@@ -0,0 +1,119 @@
+package yunyun
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity tells how bad a diagnostic is.
+type Severity uint8
+
+const (
+	// SeverityWarning is a problem that doesn't stop the page from building.
+	SeverityWarning Severity = iota
+	// SeverityError is a problem that fails the page.
+	SeverityError
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Diagnostic is a problem found while building a page.
+type Diagnostic struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Filename is the file where the problem was found.
+	Filename RelativePathFile
+	// Line is the line of the problem, starting at 1, zero if unknown.
+	Line int
+	// Column is the column of the problem, starting at 1.
+	Column int
+	// Severity tells whether it's a warning or an error.
+	Severity Severity
+	// Message describes the problem.
+	Message string
+}
+
+// String returns the diagnostic as `file:line:column: severity: message`,
+// where the position is omitted if unknown.
+func (d Diagnostic) String() string {
+	if d.Line < 1 {
+		return fmt.Sprintf("%s: %s: %s", d.Filename, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.Filename, d.Line, d.Column, d.Severity, d.Message)
+}
+
+// Diagnostics is a list of problems, which can be returned as an error.
+type Diagnostics []Diagnostic
+
+// Warn adds a warning about the file.
+func (d *Diagnostics) Warn(filename RelativePathFile, format string, args ...any) {
+	*d = append(*d, Diagnostic{Filename: filename, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+// Fail adds an error about the file.
+func (d *Diagnostics) Fail(filename RelativePathFile, format string, args ...any) {
+	*d = append(*d, Diagnostic{Filename: filename, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+// WarnAt adds a warning about the position in the file.
+func (d *Diagnostics) WarnAt(filename RelativePathFile, line, column int, format string, args ...any) {
+	*d = append(*d, Diagnostic{
+		Filename: filename, Line: line, Column: column,
+		Severity: SeverityWarning, Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// FailAt adds an error about the position in the file.
+func (d *Diagnostics) FailAt(filename RelativePathFile, line, column int, format string, args ...any) {
+	*d = append(*d, Diagnostic{
+		Filename: filename, Line: line, Column: column,
+		Severity: SeverityError, Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Sort orders the diagnostics by their files and positions.
+func (d Diagnostics) Sort() {
+	sort.SliceStable(d, func(i, j int) bool {
+		if d[i].Filename != d[j].Filename {
+			return d[i].Filename < d[j].Filename
+		}
+		if d[i].Line != d[j].Line {
+			return d[i].Line < d[j].Line
+		}
+		return d[i].Column < d[j].Column
+	})
+}
+
+// HasErrors returns true if any of the diagnostics is an error.
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the diagnostics as an error, nil if there are none.
+func (d Diagnostics) Err() error {
+	if len(d) < 1 {
+		return nil
+	}
+	return d
+}
+
+// Error returns all the diagnostics, one per line.
+func (d Diagnostics) Error() string {
+	lines := make([]string, len(d))
+	for i, diagnostic := range d {
+		lines[i] = diagnostic.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,100 @@
+package jsonfeed
+
+import "time"
+
+const (
+	// Version is the url of the JSON Feed version implemented.
+	Version = "https://jsonfeed.org/version/1.1"
+
+	// JsonFeedFormat is the date format used in the JSON Feed spec.
+	JsonFeedFormat = time.RFC3339
+
+	// JsonFeedDocs is the JSON Feed spec implemented.
+	JsonFeedDocs = "https://www.jsonfeed.org/version/1.1/"
+)
+
+// Feed is the top-level object of a JSON Feed.
+type Feed struct {
+	// Version is the url of the version of the format, always `Version`.
+	Version string `json:"version"`
+
+	// Title is the name of the feed.
+	Title string `json:"title"`
+
+	// HomePageUrl is the url of the website the feed describes (optional).
+	HomePageUrl string `json:"home_page_url,omitempty"`
+
+	// FeedUrl is the url of the feed itself (optional).
+	FeedUrl string `json:"feed_url,omitempty"`
+
+	// Description is more detail about the feed (optional).
+	Description string `json:"description,omitempty"`
+
+	// Language is the primary language of the feed, like "en-US" (optional).
+	Language string `json:"language,omitempty"`
+
+	// Authors are the authors of the feed (optional).
+	Authors []Author `json:"authors,omitempty"`
+
+	// Items are the items of the feed.
+	Items []Item `json:"items"`
+}
+
+// Item is a single entry of the feed, a page.
+type Item struct {
+	// ID is unique for the item in the feed over time, like its url.
+	ID string `json:"id"`
+
+	// Url is the url of the page (optional).
+	Url string `json:"url,omitempty"`
+
+	// Title is the title of the item (optional).
+	Title string `json:"title,omitempty"`
+
+	// ContentHtml is the html of the item, either it or `ContentText`
+	// must be present.
+	ContentHtml string `json:"content_html,omitempty"`
+
+	// ContentText is the plain text of the item, either it or
+	// `ContentHtml` must be present.
+	ContentText string `json:"content_text,omitempty"`
+
+	// Summary is a plain text sentence or two describing the item (optional).
+	Summary string `json:"summary,omitempty"`
+
+	// DatePublished is the publication date in RFC 3339 (optional).
+	DatePublished string `json:"date_published,omitempty"`
+
+	// Authors are the authors of the item (optional).
+	Authors []Author `json:"authors,omitempty"`
+
+	// Tags are plain strings associated with the item (optional).
+	Tags []string `json:"tags,omitempty"`
+
+	// Attachments are the related resources, like podcast episodes (optional).
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a resource related to the item.
+type Attachment struct {
+	// Url is the location of the attachment.
+	Url string `json:"url"`
+
+	// MimeType is the type of the attachment, like "audio/mpeg".
+	MimeType string `json:"mime_type"`
+
+	// SizeInBytes is how large the file is (optional).
+	SizeInBytes int64 `json:"size_in_bytes,omitempty"`
+
+	// DurationInSeconds is how long the file takes to play (optional).
+	DurationInSeconds int `json:"duration_in_seconds,omitempty"`
+}
+
+// Author is an author of the feed or an item.
+type Author struct {
+	// Name is the name of the author (optional).
+	Name string `json:"name,omitempty"`
+
+	// Url is the website of the author (optional).
+	Url string `json:"url,omitempty"`
+}
@@ -33,11 +33,46 @@ type Page struct {
 	HtmlHead []string
 	// Footnotes is the footnotes of the page.
 	Footnotes []string
+	// Tags are the page's tags, in the order they were given.
+	Tags []string
+	// Backlinks are the pages that link to this page.
+	Backlinks []Backlink
+	// Series is the name of the series the page is part of, if any.
+	Series string
+	// Navigation is the way to the previous and next pages, nil if none.
+	Navigation *Navigation
 	// DateHoloscene tells us whether the first paragraph
 	// on the page is given as holoscene date stamp.
 	DateHoloscene bool
 }
 
+// Backlink is a page that links to another page.
+type Backlink struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Title is the title of the linking page.
+	Title string
+	// Location is the location of the linking page.
+	Location RelativePathDir
+}
+
+// Navigation is the way to the pages around another page, either in
+// its series or in its directory.
+type Navigation struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Series is the name of the series, empty if it's the directory.
+	Series string
+	// Part is the page's position in the series, starting from one.
+	Part int
+	// Parts is the number of pages in the series.
+	Parts int
+	// Previous is the page that comes before, nil if it's the first.
+	Previous *Backlink
+	// Next is the page that comes after, nil if it's the last.
+	Next *Backlink
+}
+
 // MetaTag is a struct for holding the meta tag.
 type MetaTag struct {
 	// To prevent unkeyed literars.
@@ -1,5 +1,10 @@
 package yunyun
 
+import (
+	"slices"
+	"strings"
+)
+
 // PageOption representions a function that can be passed
 // to a new `Page` instantiation to modify the state.
 type PageOption func(*Page)
@@ -45,6 +50,16 @@ func (p *Page) Options(options ...PageOption) *Page {
 	return p
 }
 
+// HasTitle returns true if the page's title was given.
+func (p *Page) HasTitle() bool {
+	return len(p.Title) > 0 && p.Title != defaultPageTitle
+}
+
+// HasDate returns true if the page's date was given.
+func (p *Page) HasDate() bool {
+	return len(p.Date) > 0 && p.Date != defaultDate
+}
+
 // WithFilename sets the filename.
 func WithFilename(filename RelativePathFile) PageOption {
 	return func(p *Page) {
@@ -65,3 +80,19 @@ func WithContents(contents Contents) PageOption {
 		p.Contents = contents
 	}
 }
+
+// AddTags adds the tags to the page, skipping the empty and repeated ones.
+func (p *Page) AddTags(tags ...string) {
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 1 || slices.Contains(p.Tags, tag) {
+			continue
+		}
+		p.Tags = append(p.Tags, tag)
+	}
+}
+
+// HasTag tells us if the page has the tag.
+func (p *Page) HasTag(tag string) bool {
+	return slices.Contains(p.Tags, tag)
+}
@@ -168,6 +168,22 @@ var (
 	FootnotePostProcessingRegexp = regexp.MustCompile(`!(\d+)!`)
 )
 
+// MathlessRanges returns the `[start, end)` ranges of the text that can't
+// have any math, which are the verbatim text and the links but their
+// texts, as those end up in the code and the html attributes.
+func MathlessRanges(text string) [][]int {
+	ranges := VerbatimText.FindAllStringIndex(text, -1)
+	for _, match := range LinkRegexp.FindAllStringSubmatchIndex(text, -1) {
+		textStart, textEnd := match[2*linkTextIndex], match[2*linkTextIndex+1]
+		if textStart < 0 {
+			ranges = append(ranges, match[:2])
+			continue
+		}
+		ranges = append(ranges, []int{match[0], textStart}, []int{textEnd, match[1]})
+	}
+	return ranges
+}
+
 // RemoveFormatting will remove all special markup symbols.
 func RemoveFormatting(what string) string {
 	for _, source := range SpecialTextMarkups {
@@ -144,4 +144,26 @@ type Channel struct {
 	//
 	// Example: "<ttl>60</ttl>"
 	TTL int `xml:"ttl,omitempty"`
+
+	// --------------------------------
+	// Podcast elements, see `ITunesNamespace`
+	// --------------------------------
+
+	// The name of the podcast's host.
+	ITunesAuthor string `xml:"itunes:author,omitempty"`
+
+	// The artwork of the podcast.
+	ITunesImage *ITunesImage `xml:"itunes:image,omitempty"`
+
+	// The category of the podcast.
+	ITunesCategory *ITunesCategory `xml:"itunes:category,omitempty"`
+
+	// Either "true" or "false", whether the podcast has explicit content.
+	ITunesExplicit string `xml:"itunes:explicit,omitempty"`
+
+	// The contact of the podcast's owner.
+	ITunesOwner *ITunesOwner `xml:"itunes:owner,omitempty"`
+
+	// Either "episodic" or "serial", how the episodes should be listened to.
+	ITunesType string `xml:"itunes:type,omitempty"`
 }
@@ -19,5 +19,5 @@ type Enclosure struct {
 	Type string `xml:"type,attr"`
 
 	// Length of the image in bytes.
-	Length int `xml:"length,attr"`
+	Length int64 `xml:"length,attr"`
 }
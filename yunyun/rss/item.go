@@ -94,4 +94,25 @@ type Item struct {
 	// it's a date in the future, aggregators may choose to not display
 	// the item until that date.
 	PubDate string `xml:"pubDate,omitempty"`
+
+	// The full content of the item as html, which is a part of the
+	// content module, see `ContentNamespace`.
+	//
+	// Example: "<content:encoded><![CDATA[<p>Hello</p>]]></content:encoded>"
+	ContentEncoded *ContentEncoded `xml:"content:encoded,omitempty"`
+
+	// The duration of the enclosure, in seconds or as "HH:MM:SS".
+	ITunesDuration string `xml:"itunes:duration,omitempty"`
+
+	// The episode number of the item.
+	ITunesEpisode string `xml:"itunes:episode,omitempty"`
+
+	// Either "true" or "false", whether the episode has explicit content.
+	ITunesExplicit string `xml:"itunes:explicit,omitempty"`
+}
+
+// ContentEncoded is the full html content of an item.
+type ContentEncoded struct {
+	// The html itself, which is kept as is.
+	Value string `xml:",cdata"`
 }
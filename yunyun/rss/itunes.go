@@ -0,0 +1,32 @@
+package rss
+
+// ITunesNamespace is the namespace of Apple's podcast elements, which
+// podcast apps expect to find next to the enclosures.
+//
+// See more: https://podcasters.apple.com/support/823-podcast-requirements
+const ITunesNamespace = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+
+// ITunesImage is the artwork of the podcast or its episode.
+type ITunesImage struct {
+	// The url of the artwork, which should be a square jpg or png
+	// between 1400x1400 and 3000x3000 pixels.
+	Href string `xml:"href,attr"`
+}
+
+// ITunesCategory is the category that the podcast belongs to.
+//
+// Example: `<itunes:category text="Technology"/>`
+type ITunesCategory struct {
+	// One of the categories listed by Apple.
+	Text string `xml:"text,attr"`
+}
+
+// ITunesOwner is the contact of the podcast's owner, which is
+// not shown publicly.
+type ITunesOwner struct {
+	// The name of the owner.
+	Name string `xml:"itunes:name,omitempty"`
+
+	// The email of the owner.
+	Email string `xml:"itunes:email,omitempty"`
+}
@@ -14,6 +14,10 @@ const (
 
 	// RSSDocs RSS spec implemented.
 	RSSDocs = "https://www.rssboard.org/rss-specification"
+
+	// ContentNamespace is the namespace of the content module, which
+	// allows items to have their full contents.
+	ContentNamespace = "http://purl.org/rss/1.0/modules/content/"
 )
 
 // RSS document is a <rss> element, with a
@@ -31,4 +35,12 @@ type RSS struct {
 
 	// Must be "2.0"
 	Version string `xml:"version,attr"`
+
+	// ContentNamespace declares the content module if the items use
+	// it, see `ContentNamespace`.
+	ContentNamespace string `xml:"xmlns:content,attr,omitempty"`
+
+	// ITunesNamespace declares the podcast elements if the channel
+	// uses them, see `ITunesNamespace`.
+	ITunesNamespace string `xml:"xmlns:itunes,attr,omitempty"`
 }
@@ -0,0 +1,45 @@
+package search
+
+// Index is the search index of the website, which either has all the
+// pages itself or lists the shards that have them. The keys are kept
+// short, as the index is downloaded by every visitor who searches.
+type Index struct {
+	// Shards are the parts of the index, if it's sharded.
+	Shards []Shard `json:"s,omitempty"`
+
+	// Pages are the pages of the website, if it's not sharded.
+	Pages []Page `json:"p,omitempty"`
+}
+
+// Shard is the part of the index with the pages of a directory.
+type Shard struct {
+	// Directory is the top directory of the pages.
+	Directory string `json:"d"`
+
+	// Url is where the shard's index is.
+	Url string `json:"u"`
+}
+
+// Page is a single page that can be found.
+type Page struct {
+	// Title is the title of the page.
+	Title string `json:"t"`
+
+	// Url is the full url of the page.
+	Url string `json:"u"`
+
+	// Headings are the headings of the page.
+	Headings []Heading `json:"h,omitempty"`
+
+	// Text is all the plain text of the page.
+	Text string `json:"x"`
+}
+
+// Heading is a heading of a page that can be linked to.
+type Heading struct {
+	// Text is the plain text of the heading.
+	Text string `json:"t"`
+
+	// Anchor is the id of the heading on the page.
+	Anchor string `json:"a"`
+}
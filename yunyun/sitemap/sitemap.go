@@ -0,0 +1,69 @@
+package sitemap
+
+import "encoding/xml"
+
+const (
+	// Namespace is the namespace of the sitemaps protocol.
+	Namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+	// LastModFormat is the W3C datetime format for the dates.
+	LastModFormat = "2006-01-02"
+
+	// MaxURLs is the most urls that a single sitemap can have.
+	MaxURLs = 50_000
+
+	// Docs is the sitemaps protocol implemented.
+	Docs = "https://www.sitemaps.org/protocol.html"
+)
+
+// URLSet is the <urlset> root of a sitemap, which encapsulates the
+// file and references the current protocol standard.
+type URLSet struct {
+	XMLName xml.Name `xml:"urlset"`
+
+	// Namespace is the protocol standard, always `Namespace`.
+	Namespace string `xml:"xmlns,attr"`
+
+	// URLs are the entries of the sitemap, at most `MaxURLs`.
+	URLs []URL `xml:"url"`
+}
+
+// URL is a <url> entry of the sitemap.
+type URL struct {
+	XMLName xml.Name `xml:"url"`
+
+	// Loc is the full url of the page, which must begin with the
+	// protocol and be less than 2,048 characters.
+	//
+	// Example: "<loc>http://www.example.com/</loc>"
+	Loc string `xml:"loc"`
+
+	// LastMod is the date of last modification of the page in the
+	// W3C Datetime format, which can be just YYYY-MM-DD (optional).
+	//
+	// Example: "<lastmod>2005-01-01</lastmod>"
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Index is the <sitemapindex> root of a sitemap index file, which lists
+// multiple sitemaps, when there are too many urls for a single one.
+type Index struct {
+	XMLName xml.Name `xml:"sitemapindex"`
+
+	// Namespace is the protocol standard, always `Namespace`.
+	Namespace string `xml:"xmlns,attr"`
+
+	// Sitemaps are the entries of the index.
+	Sitemaps []Sitemap `xml:"sitemap"`
+}
+
+// Sitemap is a <sitemap> entry of the sitemap index.
+type Sitemap struct {
+	XMLName xml.Name `xml:"sitemap"`
+
+	// Loc is the full url of the sitemap.
+	Loc string `xml:"loc"`
+
+	// LastMod is the time the sitemap was modified (optional).
+	LastMod string `xml:"lastmod,omitempty"`
+}
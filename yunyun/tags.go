@@ -0,0 +1,17 @@
+package yunyun
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TagSlug returns the tag's directory name, which is lowercase
+// with dashes in place of spaces and slashes.
+func TagSlug(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '/' || r == '\\' {
+			return '-'
+		}
+		return unicode.ToLower(r)
+	}, strings.TrimSpace(tag))
+}